@@ -2,25 +2,84 @@
 package falcula
 
 import (
-	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"sync/atomic"
 
+	"github.com/LucasAVasco/falcula/dryrun"
+	"github.com/LucasAVasco/falcula/logfile"
 	"github.com/LucasAVasco/falcula/project"
+	"github.com/LucasAVasco/falcula/report"
+	"github.com/LucasAVasco/falcula/web"
 )
 
+// Options is the application options. All fields are optional
+type Options struct {
+	RawMode bool // Runs in raw mode (disables the TUI)
+	KeepLog bool // Keeps the session log file after exiting (overrides the project configuration)
+
+	WebAddress     string // Address of the web dashboard (e.g. ':7777'). The dashboard is disabled if empty
+	MetricsAddress string // Address of the metrics listener (e.g. '127.0.0.1:9090'). The metrics are disabled if empty
+
+	ReportJSON  string // Writes the report of the run (final state of the services) to this JSON file. Optional
+	ReportJUnit string // Writes the report of the run to this JUnit XML file. Optional
+
+	DryRun bool // Records the commands of the services instead of executing them and shows the plan. Implies raw mode
+	NoDeps bool // Does not run the dependencies of the tasks
+	Force  bool // Runs the tasks even if they are up to date
+}
+
 // App is the main application. Its is a facade to all falcula features
 type App struct {
-	rawMode   bool
-	invokeDir string
-	project   *project.Config
+	rawMode     bool
+	keepLog     bool
+	detached    bool // Runs in a background daemon (see `StartDaemon`)
+	invokeDir   string
+	webAddr     string
+	metricsAddr string
+	project     *project.Config
+
+	rerunMutex    sync.Mutex
+	onRerunScript func(args []string) error // Re-runs the current script. Used by the control server
+	shuttingDown  atomic.Bool               // The session was shut down by the control server or a signal
+
+	webServer *web.Server // Web dashboard of the session. Nil if disabled
+
+	reportJSON      string
+	reportJUnit     string
+	reportCollector *report.Collector // Services of the current run. Nil if the report is disabled (see `reportEnabled`)
+
+	dryRunPlan *dryrun.Plan // Commands recorded by the dry run. Nil if not a dry run
+	noDeps     bool         // Does not run the dependencies of the tasks
+	force      bool         // Runs the tasks even if they are up to date
 }
 
-// NewApp creates a new app instance. The `rawMode` parameter is used to determine if the falcula should run in raw mode (disables the TUI)
-func NewApp(rawMode bool) (*App, error) {
+// NewApp creates a new app instance. The options are optional
+func NewApp(opts *Options) (*App, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
 	a := &App{
-		rawMode: rawMode,
+		rawMode:     opts.RawMode || opts.DryRun || IsDaemon(), // A daemon has no terminal to show the TUI
+		keepLog:     opts.KeepLog,
+		detached:    IsDaemon(),
+		webAddr:     opts.WebAddress,
+		metricsAddr: opts.MetricsAddress,
+		reportJSON:  opts.ReportJSON,
+		reportJUnit: opts.ReportJUnit,
+		noDeps:      opts.NoDeps,
+		force:       opts.Force,
+	}
+
+	if opts.DryRun {
+		a.dryRunPlan = dryrun.New()
+	}
+
+	if a.reportEnabled() {
+		a.reportCollector = report.NewCollector()
 	}
 
 	// Invoke directory
@@ -37,12 +96,7 @@ func NewApp(rawMode bool) (*App, error) {
 	a.invokeDir = invokeDir
 
 	// Project
-	projectFile, err := getProjectFilePath()
-	if err != nil {
-		return nil, fmt.Errorf("error getting project file path: %w", err)
-	}
-
-	a.project, err = project.ReadConfigFile(projectFile)
+	a.project, err = project.FindConfig(invokeDir)
 	if err != nil {
 		return nil, fmt.Errorf("error reading project file (falcula.yaml): %w", err)
 	}
@@ -50,36 +104,11 @@ func NewApp(rawMode bool) (*App, error) {
 	return a, nil
 }
 
-// getProjectFilePath returns the path to the falcula configuration file that the application should use
-func getProjectFilePath() (string, error) {
-	currentDir, err := os.Getwd()
-	if err != nil {
-		return "", fmt.Errorf("error getting current working directory: %w", err)
-	}
-
-	currentDir, err = filepath.Abs(currentDir)
-	if err != nil {
-		return "", fmt.Errorf("error getting absolute path of current working directory: %w", err)
-	}
-
-	// Iterate from the current directory to the root searching for a 'falcula.yaml' file
-	var projectFile string
-	for {
-		projectFile = currentDir + "/falcula.yaml"
-		_, err = os.Stat(projectFile)
-		if err == nil {
-			break
-		} else if !errors.Is(err, os.ErrNotExist) {
-			return "", fmt.Errorf("error checking if file '%s' exists: %w", projectFile, err)
-		}
-
-		if currentDir == "/" {
-			return "", fmt.Errorf("project file 'falcula.yaml' not found")
-		}
-
-		// Next directory
-		currentDir = filepath.Dir(currentDir)
+// GetLogDir returns the directory of the session log files of the current project
+func (a *App) GetLogDir() (string, error) {
+	if a.project.Log.Dir != "" {
+		return a.project.Log.Dir, nil
 	}
 
-	return projectFile, nil
+	return logfile.GetDefaultDir()
 }
@@ -1,6 +1,7 @@
 package falcula
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 )
@@ -15,3 +16,17 @@ func (a *App) configureCmd(cmd *exec.Cmd, projectFolder string) {
 	cmd.Env = append(cmd.Env, "FALCULA_PROJECT_DIR="+projectFolder)
 	cmd.Env = append(cmd.Env, "FALCULA_INVOKE_DIR="+a.invokeDir)
 }
+
+// newFalculaCmd creates a command that runs Falcula (the current executable) with the provided arguments in the provided directory. Used to
+// run tasks and scripts in other processes
+func newFalculaCmd(dir string, args ...string) (*exec.Cmd, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("error getting path of the falcula executable: %w", err)
+	}
+
+	cmd := exec.Command(executable, args...)
+	cmd.Dir = dir
+
+	return cmd, nil
+}
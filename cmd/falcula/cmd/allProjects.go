@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/LucasAVasco/falcula"
+	"github.com/spf13/cobra"
+)
+
+// addAllProjectsFlags adds the flags to run a script or task in all child projects (see `getAllProjectsOptions`)
+func addAllProjectsFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("all-projects", false, "Run in every child project (recursively) that defines it, in parallel")
+	cmd.Flags().String("projects", "", "Only run in the child projects whose name matches this glob (implies --all-projects)")
+	cmd.Flags().Int("concurrency", 0, "Maximum number of child projects running at the same time with --all-projects (default: number of CPUs)")
+}
+
+// getAllProjectsOptions returns the options to run a script or task in all child projects. Returns nil if the user did not request it
+func getAllProjectsOptions(cmd *cobra.Command) (*falcula.AllProjectsOptions, error) {
+	allProjects, err := cmd.Flags().GetBool("all-projects")
+	if err != nil {
+		return nil, fmt.Errorf("error getting value of 'all-projects' flag: %w", err)
+	}
+
+	projects, err := cmd.Flags().GetString("projects")
+	if err != nil {
+		return nil, fmt.Errorf("error getting value of 'projects' flag: %w", err)
+	}
+
+	concurrency, err := cmd.Flags().GetInt("concurrency")
+	if err != nil {
+		return nil, fmt.Errorf("error getting value of 'concurrency' flag: %w", err)
+	}
+
+	if !allProjects && projects == "" {
+		return nil, nil
+	}
+
+	return &falcula.AllProjectsOptions{
+		Projects:    projects,
+		Concurrency: concurrency,
+	}, nil
+}
+
+// runInAllProjects runs a script or task in all child projects with a new app. The runs do not have a TUI
+func runInAllProjects(cmd *cobra.Command, run func(app *falcula.App) error) error {
+	cmd.SilenceUsage = true // The errors of the runs are not usage errors
+
+	detach, err := cmd.Flags().GetBool("detach")
+	if err != nil {
+		return fmt.Errorf("error getting value of 'detach' flag: %w", err)
+	}
+
+	if detach {
+		return errors.New("a run in all projects can not be detached")
+	}
+
+	app, err := createFalculaApp(cmd)
+	if err != nil {
+		return fmt.Errorf("error creating falcula app: %w", err)
+	}
+
+	return run(app)
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/LucasAVasco/falcula/control"
+	"github.com/LucasAVasco/falcula/lua/modules/modtui/tui/remote"
+	"github.com/spf13/cobra"
+)
+
+// attachCmd represents the attach command
+var attachCmd = &cobra.Command{
+	Use:   "attach [session]",
+	Short: "Open the TUI of a running session",
+	Long: `Open the TUI of a running session, usually a detached one ('falcula run <script> --detach'). Attaches to the newest session if no
+session is provided. The session can be abbreviated to any unique prefix of its name.
+
+The TUI shows the buffered logs of the session and the new ones. Press 'q' to detach (the session keeps running) and 'Q' to shut down the
+session.`,
+	Example: `
+falcula attach
+
+falcula attach falcula-20260101-120000`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		session := ""
+		if len(args) > 0 {
+			session = args[0]
+		}
+
+		socket, err := control.FindSocket(session)
+		if err != nil {
+			return err
+		}
+
+		err = remote.Attach(socket.Path)
+		if errors.Is(err, remote.ErrSessionEnded) {
+			fmt.Printf("Session '%s' ended\n", socket.Session)
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("error attaching to session '%s': %w", socket.Session, err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(attachCmd)
+}
@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Configuration commands",
+	Long:  `Project configuration (falcula.yaml) related commands.`,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+}
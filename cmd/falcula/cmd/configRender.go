@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// configRenderCmd represents the configRender command
+var configRenderCmd = &cobra.Command{
+	Use:   "render [project]",
+	Short: "Print the resolved configuration",
+	Long: `Print the configuration of the current project, or of a child project, with the resolved variables and templates and the absolute
+paths.
+
+You can access inner projects using the following syntax: "innerProject1:innerProject2"`,
+	Example: `
+falcula config render
+
+falcula config render innerProject1:innerProject2`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		app, err := createFalculaApp(cmd)
+		if err != nil {
+			return fmt.Errorf("error creating falcula app: %w", err)
+		}
+
+		projectName := ""
+		if len(args) > 0 {
+			projectName = args[0]
+		}
+
+		config, err := app.RenderConfig(projectName)
+		if err != nil {
+			return fmt.Errorf("error rendering configuration: %w", err)
+		}
+
+		_, err = os.Stdout.Write(config)
+		return err
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configRenderCmd)
+}
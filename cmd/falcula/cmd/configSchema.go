@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/LucasAVasco/falcula/project"
+	"github.com/spf13/cobra"
+)
+
+// configSchemaCmd represents the configSchema command
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of the configuration file",
+	Long: `Print the JSON Schema of the project configuration file (falcula.yaml). Editors can use it to validate and complete the configuration
+(e.g. with the '# yaml-language-server: $schema=<path>' comment of the YAML language server).`,
+	Example: `
+falcula config schema > falcula.schema.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		schema, err := project.GetJSONSchema()
+		if err != nil {
+			return fmt.Errorf("error generating JSON Schema: %w", err)
+		}
+
+		_, err = os.Stdout.Write(schema)
+		return err
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configSchemaCmd)
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// configValidateCmd represents the configValidate command
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate the configuration",
+	Long: `Validate the configuration of the current project and its child projects. Besides the errors of the configuration files (e.g. unknown
+fields, with their line and column), checks that the working directories, shell and Lua files, fallback scripts and tasks, task dependencies
+and child projects exist.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true // The problems of the configuration are not usage errors
+
+		app, err := createFalculaApp(cmd)
+		if err != nil {
+			return fmt.Errorf("error creating falcula app: %w", err)
+		}
+
+		problems := app.ValidateConfig()
+		for _, problem := range problems {
+			fmt.Println(problem)
+		}
+
+		if len(problems) > 0 {
+			return fmt.Errorf("the configuration has %d problems", len(problems))
+		}
+
+		fmt.Println("The configuration is valid")
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configValidateCmd)
+}
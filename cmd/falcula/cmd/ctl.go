@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/LucasAVasco/falcula/control"
+	"github.com/spf13/cobra"
+)
+
+// ctlCmd represents the ctl command
+var ctlCmd = &cobra.Command{
+	Use:   "ctl",
+	Short: "Control a running session",
+	Long: `Control a running session through its control socket. Including listing the services, starting, stopping and restarting them,
+reading their logs and re-running the script with new arguments.
+
+Every session (script or task) listens on a Unix socket in the default temporary directory. The commands use the newest session if
+'--session' is not provided. The session can be abbreviated to any unique prefix of its name.
+
+The services are selected by a target: the name of a service, the name of a manager (all its services) or '<manager>/<service>'. All
+services are selected if no target is provided.`,
+}
+
+func init() {
+	rootCmd.AddCommand(ctlCmd)
+	ctlCmd.PersistentFlags().StringP("session", "s", "", "Session to control (newest if empty)")
+}
+
+// dialSession connects to the control server of the session selected by the 'session' flag
+func dialSession(cmd *cobra.Command) (*control.Client, error) {
+	session, err := cmd.Flags().GetString("session")
+	if err != nil {
+		return nil, fmt.Errorf("error getting value of 'session' flag: %w", err)
+	}
+
+	socket, err := control.FindSocket(session)
+	if err != nil {
+		return nil, err
+	}
+
+	return control.Dial(socket.Path)
+}
+
+// runCtlAction runs an action (start, stop, restart, enable or disable) on the services selected by the arguments
+func runCtlAction(cmd *cobra.Command, method string, args []string) error {
+	params := control.TargetParams{}
+	if len(args) > 0 {
+		params.Target = args[0]
+	}
+
+	if cmd.Flags().Lookup("force") != nil {
+		force, err := cmd.Flags().GetBool("force")
+		if err != nil {
+			return fmt.Errorf("error getting value of 'force' flag: %w", err)
+		}
+		params.Force = force
+	}
+
+	client, err := dialSession(cmd)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	err = client.Call(method, &params, nil)
+	if err != nil {
+		return fmt.Errorf("error running '%s': %w", method, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"github.com/LucasAVasco/falcula/control"
+	"github.com/spf13/cobra"
+)
+
+// ctlDisableCmd represents the ctlDisable command
+var ctlDisableCmd = &cobra.Command{
+	Use:   "disable [target]",
+	Short: "Disable services",
+	Long:  `Disable the selected services. The services must be stopped. Disabled services can not be started until enabled again.`,
+	Example: `
+falcula ctl disable db
+
+falcula ctl disable postgres/db`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCtlAction(cmd, control.MethodDisable, args)
+	},
+}
+
+func init() {
+	ctlCmd.AddCommand(ctlDisableCmd)
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"github.com/LucasAVasco/falcula/control"
+	"github.com/spf13/cobra"
+)
+
+// ctlEnableCmd represents the ctlEnable command
+var ctlEnableCmd = &cobra.Command{
+	Use:   "enable [target]",
+	Short: "Enable services",
+	Long:  `Enable the selected services. They can be started again.`,
+	Example: `
+falcula ctl enable db
+
+falcula ctl enable postgres/db`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCtlAction(cmd, control.MethodEnable, args)
+	},
+}
+
+func init() {
+	ctlCmd.AddCommand(ctlEnableCmd)
+}
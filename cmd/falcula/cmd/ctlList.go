@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/LucasAVasco/falcula/control"
+	"github.com/spf13/cobra"
+)
+
+// ctlListCmd represents the ctlList command
+var ctlListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the managers and services of a session",
+	Long:  `List the service managers of a running session and the status of their services.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := dialSession(cmd)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		managers := []*control.ManagerInfo{}
+		err = client.Call(control.MethodList, nil, &managers)
+		if err != nil {
+			return fmt.Errorf("error listing managers: %w", err)
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "MANAGER\tSERVICE\tSTATUS")
+		for _, man := range managers {
+			for _, svc := range man.Services {
+				fmt.Fprintf(writer, "%s\t%s\t%s\n", man.Name, svc.Name, svc.Status)
+			}
+		}
+
+		return writer.Flush()
+	},
+}
+
+func init() {
+	ctlCmd.AddCommand(ctlListCmd)
+}
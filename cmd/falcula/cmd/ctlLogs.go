@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/LucasAVasco/falcula/control"
+	"github.com/LucasAVasco/falcula/logsink"
+	"github.com/spf13/cobra"
+)
+
+// ctlLogsCmd represents the ctlLogs command
+var ctlLogsCmd = &cobra.Command{
+	Use:   "logs [service]",
+	Short: "Show the logs of services",
+	Long: `Show the last logs of the services of a running session. The service can be a glob. Shows the logs of all services if no service is
+provided.
+
+The session only buffers the last lines of its services. Use 'falcula logs' to read the full session log file.`,
+	Example: `
+falcula ctl logs
+
+falcula ctl logs -f db
+
+falcula ctl logs -n 100 'api-*'`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := control.LogsParams{}
+		if len(args) > 0 {
+			params.Service = args[0]
+		}
+
+		var err error
+		params.Lines, err = cmd.Flags().GetInt("lines")
+		if err != nil {
+			return fmt.Errorf("error getting value of 'lines' flag: %w", err)
+		}
+
+		params.Follow, err = cmd.Flags().GetBool("follow")
+		if err != nil {
+			return fmt.Errorf("error getting value of 'follow' flag: %w", err)
+		}
+
+		params.Debug, err = cmd.Flags().GetBool("debug")
+		if err != nil {
+			return fmt.Errorf("error getting value of 'debug' flag: %w", err)
+		}
+
+		client, err := dialSession(cmd)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		err = client.Stream(control.MethodLogs, &params, nil, func(method string, data json.RawMessage) error {
+			if method != control.NotificationLog && method != control.NotificationDebug {
+				return nil
+			}
+
+			entry := control.LogEntry{}
+			err := json.Unmarshal(data, &entry)
+			if err != nil {
+				return fmt.Errorf("error decoding log entry: %w", err)
+			}
+
+			if method == control.NotificationDebug {
+				fmt.Print(entry.Line)
+			} else {
+				fmt.Print(logsink.FormatSyslog(entry.ToSinkEntry()))
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("error reading logs: %w", err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	ctlCmd.AddCommand(ctlLogsCmd)
+	ctlLogsCmd.Flags().IntP("lines", "n", 10, "Number of previous lines to show (negative to show all buffered lines)")
+	ctlLogsCmd.Flags().BoolP("follow", "f", false, "Keep showing the new logs until the session exits")
+	ctlLogsCmd.Flags().Bool("debug", false, "Also show the debug messages of Falcula (only detached sessions)")
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/LucasAVasco/falcula/control"
+	"github.com/spf13/cobra"
+)
+
+// ctlRerunCmd represents the ctlRerun command
+var ctlRerunCmd = &cobra.Command{
+	Use:   "rerun [args...]",
+	Short: "Re-run the script with new arguments",
+	Long: `Re-run the script of a running session with new arguments. Stops the current services before running the script again. Only
+scripts and tasks defined by a Lua file can be re-run.
+
+Use '--' before arguments that start with a dash.`,
+	Example: `
+falcula ctl rerun dev
+
+falcula ctl rerun -- --verbose`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := dialSession(cmd)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		err = client.Call(control.MethodRerun, &control.RerunParams{Args: args}, nil)
+		if err != nil {
+			return fmt.Errorf("error re-running script: %w", err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	ctlCmd.AddCommand(ctlRerunCmd)
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"github.com/LucasAVasco/falcula/control"
+	"github.com/spf13/cobra"
+)
+
+// ctlRestartCmd represents the ctlRestart command
+var ctlRestartCmd = &cobra.Command{
+	Use:   "restart [target]",
+	Short: "Restart services",
+	Long:  `Restart the selected services.`,
+	Example: `
+falcula ctl restart db
+
+falcula ctl restart postgres/db`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCtlAction(cmd, control.MethodRestart, args)
+	},
+}
+
+func init() {
+	ctlCmd.AddCommand(ctlRestartCmd)
+	ctlRestartCmd.Flags().Bool("force", false, "Kill the services instead of a graceful shutdown")
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/LucasAVasco/falcula/control"
+	"github.com/spf13/cobra"
+)
+
+// ctlShutdownCmd represents the ctlShutdown command
+var ctlShutdownCmd = &cobra.Command{
+	Use:   "shutdown",
+	Short: "End a session",
+	Long:  `Stop the services of a running session and end it. Also closes its TUI. Required to end a detached session.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := dialSession(cmd)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		err = client.Call(control.MethodShutdown, nil, nil)
+		if err != nil {
+			return fmt.Errorf("error shutting down session: %w", err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	ctlCmd.AddCommand(ctlShutdownCmd)
+}
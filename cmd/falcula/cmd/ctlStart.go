@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"github.com/LucasAVasco/falcula/control"
+	"github.com/spf13/cobra"
+)
+
+// ctlStartCmd represents the ctlStart command
+var ctlStartCmd = &cobra.Command{
+	Use:   "start [target]",
+	Short: "Start services",
+	Long:  `Start the selected services. Fails if a service is already running or disabled.`,
+	Example: `
+falcula ctl start db
+
+falcula ctl start postgres/db`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCtlAction(cmd, control.MethodStart, args)
+	},
+}
+
+func init() {
+	ctlCmd.AddCommand(ctlStartCmd)
+}
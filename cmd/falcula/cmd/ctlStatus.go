@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/LucasAVasco/falcula/control"
+	"github.com/spf13/cobra"
+)
+
+// ctlStatusCmd represents the ctlStatus command
+var ctlStatusCmd = &cobra.Command{
+	Use:   "status [target]",
+	Short: "Show the status of services",
+	Long:  `Show the status, readiness and metadata (set by output triggers or Lua) of the selected services.`,
+	Example: `
+falcula ctl status
+
+falcula ctl status db`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := control.TargetParams{}
+		if len(args) > 0 {
+			params.Target = args[0]
+		}
+
+		client, err := dialSession(cmd)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		services := []*control.ServiceInfo{}
+		err = client.Call(control.MethodStatus, &params, &services)
+		if err != nil {
+			return fmt.Errorf("error getting status: %w", err)
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "MANAGER\tSERVICE\tSTATUS\tREADY\tMETADATA")
+		for _, svc := range services {
+			metadata := make([]string, 0, len(svc.Metadata))
+			for _, key := range slices.Sorted(maps.Keys(svc.Metadata)) {
+				metadata = append(metadata, key+"="+svc.Metadata[key])
+			}
+
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%t\t%s\n", svc.Manager, svc.Name, svc.Status, svc.Ready, strings.Join(metadata, " "))
+		}
+
+		return writer.Flush()
+	},
+}
+
+func init() {
+	ctlCmd.AddCommand(ctlStatusCmd)
+}
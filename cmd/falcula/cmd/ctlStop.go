@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"github.com/LucasAVasco/falcula/control"
+	"github.com/spf13/cobra"
+)
+
+// ctlStopCmd represents the ctlStop command
+var ctlStopCmd = &cobra.Command{
+	Use:   "stop [target]",
+	Short: "Stop services",
+	Long:  `Stop (or abort the preparation of) the selected services.`,
+	Example: `
+falcula ctl stop db
+
+falcula ctl stop postgres/db`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCtlAction(cmd, control.MethodStop, args)
+	},
+}
+
+func init() {
+	ctlCmd.AddCommand(ctlStopCmd)
+	ctlStopCmd.Flags().Bool("force", false, "Kill the services instead of a graceful shutdown")
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+
+	"github.com/LucasAVasco/falcula/logfile"
+	"github.com/LucasAVasco/falcula/logsink"
+	"github.com/spf13/cobra"
+)
+
+// logsCmd represents the logs command
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Session logs commands",
+	Long: `Session logs related commands. Including listing and reading the logs of kept sessions.
+
+The sessions are read from the log directory of the current project ('log.dir' in 'falcula.yaml') or from the default temporary directory
+if there is no project or it does not configure a log directory. Use '--keep-log' (or 'log.keep' in 'falcula.yaml') to keep the log of a
+session after exiting.`,
+}
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+	logsCmd.PersistentFlags().String("dir", "", "Log directory (overrides the project configuration)")
+}
+
+// getLogDir returns the log directory to read the sessions from
+func getLogDir(cmd *cobra.Command) (string, error) {
+	dir, err := cmd.Flags().GetString("dir")
+	if err != nil {
+		return "", fmt.Errorf("error getting value of 'dir' flag: %w", err)
+	}
+
+	if dir != "" {
+		return dir, nil
+	}
+
+	// Uses the default directory if there is no project
+	app, err := createFalculaApp(cmd)
+	if err != nil {
+		return logfile.GetDefaultDir()
+	}
+
+	return app.GetLogDir()
+}
+
+// logLineFilter filters the lines of a session log file
+type logLineFilter struct {
+	service string         // Glob matched against the service name. Disabled if empty
+	grep    *regexp.Regexp // Matched against the message. Disabled if nil
+}
+
+// addLogLineFilterFlags adds the flags used by `newLogLineFilter` to a command
+func addLogLineFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().String("service", "", "Only show the logs of the services that match this glob")
+	cmd.Flags().String("grep", "", "Only show the logs whose message matches this regular expression")
+}
+
+// newLogLineFilter creates a filter from the flags of a command
+func newLogLineFilter(cmd *cobra.Command) (*logLineFilter, error) {
+	service, err := cmd.Flags().GetString("service")
+	if err != nil {
+		return nil, fmt.Errorf("error getting value of 'service' flag: %w", err)
+	}
+
+	_, err = path.Match(service, "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid service glob '%s': %w", service, err)
+	}
+
+	grep, err := cmd.Flags().GetString("grep")
+	if err != nil {
+		return nil, fmt.Errorf("error getting value of 'grep' flag: %w", err)
+	}
+
+	filter := logLineFilter{
+		service: service,
+	}
+
+	if grep != "" {
+		filter.grep, err = regexp.Compile(grep)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression '%s': %w", grep, err)
+		}
+	}
+
+	return &filter, nil
+}
+
+// match checks if a line of a session log file matches the filter. Lines that can not be parsed only match if the filter is disabled
+func (f *logLineFilter) match(line string) bool {
+	if f.service == "" && f.grep == nil {
+		return true
+	}
+
+	entry, err := logsink.ParseSyslog(line)
+	if err != nil {
+		return false
+	}
+
+	if f.service != "" {
+		matched, _ := path.Match(f.service, entry.Service)
+		if !matched {
+			return false
+		}
+	}
+
+	if f.grep != nil && !f.grep.MatchString(entry.Message) {
+		return false
+	}
+
+	return true
+}
+
+// forEachMatchingLine calls the callback for each line of the reader that matches the filter. The line does not include the newline
+func (f *logLineFilter) forEachMatchingLine(reader io.Reader, callback func(line string)) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if f.match(line) {
+			callback(line)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// getSessionFromArgs returns the session selected by the arguments of a command (the newest session if there is no argument)
+func getSessionFromArgs(cmd *cobra.Command, args []string) (*logfile.Session, error) {
+	dir, err := getLogDir(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("error getting log directory: %w", err)
+	}
+
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	session, err := logfile.FindSession(dir, name)
+	if err != nil {
+		return nil, fmt.Errorf("error finding session: %w", err)
+	}
+
+	return session, nil
+}
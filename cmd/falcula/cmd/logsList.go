@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/LucasAVasco/falcula/logfile"
+	"github.com/LucasAVasco/falcula/units"
+	"github.com/spf13/cobra"
+)
+
+// logsListCmd represents the logsList command
+var logsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List kept sessions",
+	Long:  `List the sessions kept in the log directory, from the oldest to the newest.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := getLogDir(cmd)
+		if err != nil {
+			return fmt.Errorf("error getting log directory: %w", err)
+		}
+
+		sessions, err := logfile.ListSessions(dir)
+		if err != nil {
+			return fmt.Errorf("error listing sessions: %w", err)
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "SESSION\tMODIFIED\tSIZE\tCOMPRESSED")
+		for _, session := range sessions {
+			fmt.Fprintf(
+				writer,
+				"%s\t%s\t%s\t%t\n",
+				session.Name,
+				session.ModTime.Format(time.DateTime),
+				units.FormatBytes(session.Size),
+				session.Compressed,
+			)
+		}
+
+		return writer.Flush()
+	},
+}
+
+func init() {
+	logsCmd.AddCommand(logsListCmd)
+}
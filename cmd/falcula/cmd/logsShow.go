@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// logsShowCmd represents the logsShow command
+var logsShowCmd = &cobra.Command{
+	Use:   "show [session]",
+	Short: "Show the logs of a session",
+	Long: `Show the logs of a kept session. Shows the newest session if no session is provided.
+
+The session can be abbreviated to a unique prefix of its name.`,
+	Example: `
+falcula logs show
+
+falcula logs show myproject-20260101-120000
+
+falcula logs show --service 'api*' --grep 'error|panic'`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filter, err := newLogLineFilter(cmd)
+		if err != nil {
+			return err
+		}
+
+		session, err := getSessionFromArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		reader, err := session.Open()
+		if err != nil {
+			return fmt.Errorf("error opening session '%s': %w", session.Name, err)
+		}
+		defer reader.Close()
+
+		err = filter.forEachMatchingLine(reader, func(line string) {
+			fmt.Println(line)
+		})
+		if err != nil {
+			return fmt.Errorf("error reading session '%s': %w", session.Name, err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	logsCmd.AddCommand(logsShowCmd)
+	addLogLineFilterFlags(logsShowCmd)
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// logsTailCmd represents the logsTail command
+var logsTailCmd = &cobra.Command{
+	Use:   "tail [session]",
+	Short: "Show the last logs of a session",
+	Long: `Show the last logs of a session. Uses the newest session if no session is provided.
+
+With '--follow', keeps reading the new logs of the session (the session must not be compressed). Useful to read the logs of a running
+session started with '--keep-log'.`,
+	Example: `
+falcula logs tail
+
+falcula logs tail -n 50 --service db
+
+falcula logs tail -f --grep 'error'`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		numLines, err := cmd.Flags().GetInt("lines")
+		if err != nil {
+			return fmt.Errorf("error getting value of 'lines' flag: %w", err)
+		}
+
+		follow, err := cmd.Flags().GetBool("follow")
+		if err != nil {
+			return fmt.Errorf("error getting value of 'follow' flag: %w", err)
+		}
+
+		filter, err := newLogLineFilter(cmd)
+		if err != nil {
+			return err
+		}
+
+		session, err := getSessionFromArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		if follow && session.Compressed {
+			return fmt.Errorf("can not follow the compressed session '%s'", session.Name)
+		}
+
+		reader, err := session.Open()
+		if err != nil {
+			return fmt.Errorf("error opening session '%s': %w", session.Name, err)
+		}
+		defer reader.Close()
+
+		// Last lines
+		lastLines := make([]string, 0, numLines)
+		err = filter.forEachMatchingLine(reader, func(line string) {
+			if numLines <= 0 {
+				return
+			}
+
+			if len(lastLines) == numLines {
+				lastLines = lastLines[1:]
+			}
+			lastLines = append(lastLines, line)
+		})
+		if err != nil {
+			return fmt.Errorf("error reading session '%s': %w", session.Name, err)
+		}
+
+		for _, line := range lastLines {
+			fmt.Println(line)
+		}
+
+		if !follow {
+			return nil
+		}
+
+		// Follows the new lines. The reader is at the end of the file (the scanner reads until EOF)
+		return followLogLines(reader, filter)
+	},
+}
+
+// followLogLines prints the lines appended to the reader that match the filter. Never returns unless an error happens
+func followLogLines(reader io.Reader, filter *logLineFilter) error {
+	bufReader := bufio.NewReader(reader)
+	partialLine := ""
+
+	for {
+		data, err := bufReader.ReadString('\n')
+		partialLine += data
+
+		if errors.Is(err, io.EOF) {
+			time.Sleep(200 * time.Millisecond)
+			continue
+		} else if err != nil {
+			return fmt.Errorf("error reading session: %w", err)
+		}
+
+		line := strings.TrimSuffix(partialLine, "\n")
+		partialLine = ""
+		if filter.match(line) {
+			fmt.Fprintln(os.Stdout, line)
+		}
+	}
+}
+
+func init() {
+	logsCmd.AddCommand(logsTailCmd)
+	addLogLineFilterFlags(logsTailCmd)
+	logsTailCmd.Flags().IntP("lines", "n", 10, "Number of lines to show")
+	logsTailCmd.Flags().BoolP("follow", "f", false, "Keep showing the new logs of the session")
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/LucasAVasco/falcula/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// parseRunArgs parses the Falcula flags provided after the script or task name (e.g. 'falcula run dev --raw'). The commands that run
+// scripts and tasks do not parse the flags after the name, so the parameters of the script or task (e.g. '--target=linux') are not
+// rejected as unknown flags. Returns the arguments of the script or task (parameters and positional arguments) and whether the help of
+// the script or task was requested ('--help')
+func parseRunArgs(cmd *cobra.Command, args []string) ([]string, bool, error) {
+	runArgs := []string{}
+	flags := []string{}
+	help := false
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			runArgs = append(runArgs, args[i:]...)
+			break
+		}
+
+		if arg == "--help" || arg == "-h" {
+			help = true
+			continue
+		}
+
+		var flag *pflag.Flag
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if strings.HasPrefix(arg, "--") {
+			flag = cmd.Flags().Lookup(name)
+		} else if strings.HasPrefix(arg, "-") && len(name) == 1 {
+			flag = cmd.Flags().ShorthandLookup(name)
+		}
+
+		if flag == nil {
+			runArgs = append(runArgs, arg)
+			continue
+		}
+
+		// Flags that are not booleans receive the value in the next argument if it is not provided with '='
+		flags = append(flags, arg)
+		if !hasValue && flag.NoOptDefVal == "" && i+1 < len(args) {
+			i++
+			flags = append(flags, args[i])
+		}
+	}
+
+	err := cmd.Flags().Parse(flags)
+	if err != nil {
+		return nil, false, fmt.Errorf("error parsing flags: %w", err)
+	}
+
+	return runArgs, help, nil
+}
+
+// printRunHelp prints the usage and parameters of a script or task, and the flags of the command that runs it
+func printRunHelp(cmd *cobra.Command, name string, params project.Params) error {
+	fmt.Printf("Usage:\n  %s %s [parameters] [arguments...]\n", cmd.CommandPath(), name)
+
+	if len(params) > 0 {
+		fmt.Println("\nParameters:")
+		err := params.WriteHelp(os.Stdout)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("\nFlags:\n%s", cmd.Flags().FlagUsages())
+	return nil
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestParseRunArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string // Arguments after the task name
+		wantArgs []string
+		wantHelp bool
+		wantSet  map[string]string // Falcula flags set by the arguments
+	}{
+		{
+			name:     "raw flag",
+			args:     []string{"--raw"},
+			wantArgs: []string{},
+			wantSet:  map[string]string{"raw": "true"},
+		},
+		{
+			name:     "help",
+			args:     []string{"--help"},
+			wantArgs: []string{},
+			wantHelp: true,
+		},
+		{
+			name:     "short help",
+			args:     []string{"-h"},
+			wantArgs: []string{},
+			wantHelp: true,
+		},
+		{
+			name:     "task flags",
+			args:     []string{"--no-deps", "--force"},
+			wantArgs: []string{},
+			wantSet:  map[string]string{"no-deps": "true", "force": "true"},
+		},
+		{
+			name:     "flag with value in the next argument",
+			args:     []string{"--concurrency", "4", "--target=linux"},
+			wantArgs: []string{"--target=linux"},
+			wantSet:  map[string]string{"concurrency": "4"},
+		},
+		{
+			name:     "parameters and positional arguments",
+			args:     []string{"--env=staging", "--raw", "--replicas", "3", "file.txt"},
+			wantArgs: []string{"--env=staging", "--replicas", "3", "file.txt"},
+			wantSet:  map[string]string{"raw": "true"},
+		},
+		{
+			name:     "arguments after the separator",
+			args:     []string{"--raw", "--", "--force", "--help"},
+			wantArgs: []string{"--", "--force", "--help"},
+			wantSet:  map[string]string{"raw": "true"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Merges the persistent flags of the root command, as cobra does before running the command
+			err := taskRunCmd.ParseFlags([]string{})
+			if err != nil {
+				t.Fatalf("error merging flags: %v", err)
+			}
+			t.Cleanup(func() { resetFlags(taskRunCmd.Flags()) })
+
+			args, help, err := parseRunArgs(taskRunCmd, test.args)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !slices.Equal(args, test.wantArgs) {
+				t.Errorf("got arguments %q, expected %q", args, test.wantArgs)
+			}
+			if help != test.wantHelp {
+				t.Errorf("got help %v, expected %v", help, test.wantHelp)
+			}
+
+			taskRunCmd.Flags().VisitAll(func(flag *pflag.Flag) {
+				want, ok := test.wantSet[flag.Name]
+				if ok != flag.Changed {
+					t.Errorf("flag '%s' changed: %v, expected %v", flag.Name, flag.Changed, ok)
+				} else if ok && flag.Value.String() != want {
+					t.Errorf("flag '%s' is %q, expected %q", flag.Name, flag.Value.String(), want)
+				}
+			})
+		})
+	}
+}
+
+// resetFlags sets the flags to their default values
+func resetFlags(flags *pflag.FlagSet) {
+	flags.VisitAll(func(flag *pflag.Flag) {
+		flag.Value.Set(flag.DefValue)
+		flag.Changed = false
+	})
+}
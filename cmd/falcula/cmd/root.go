@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"os/exec"
 
 	"github.com/LucasAVasco/falcula"
 	"github.com/spf13/cobra"
@@ -19,15 +22,23 @@ Falcula exposes a set of Lua modules to create and manage services and container
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+//
+// Exits with the exit code of the failed command of a script or task, or with 1 on other errors
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
-		panic(err)
+		exitErr := &exec.ExitError{}
+		if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			os.Exit(exitErr.ExitCode())
+		}
+
+		os.Exit(1)
 	}
 }
 
 func init() {
 	rootCmd.PersistentFlags().Bool("raw", false, "Run in raw mode (disables TUI)")
+	rootCmd.PersistentFlags().Bool("keep-log", false, "Keep the session log file after exiting")
 }
 
 // createFalculaApp create a new falcula application
@@ -37,10 +48,109 @@ func createFalculaApp(cmd *cobra.Command) (*falcula.App, error) {
 		return nil, fmt.Errorf("error getting value of 'raw' flag: %w", err)
 	}
 
-	app, err := falcula.NewApp(rawMode)
+	keepLog, err := cmd.Flags().GetBool("keep-log")
+	if err != nil {
+		return nil, fmt.Errorf("error getting value of 'keep-log' flag: %w", err)
+	}
+
+	dryRun := false
+	if cmd.Flags().Lookup("dry-run") != nil {
+		dryRun, err = cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return nil, fmt.Errorf("error getting value of 'dry-run' flag: %w", err)
+		}
+	}
+
+	// Only the 'task run' command has these flags
+	taskFlags := map[string]bool{"no-deps": false, "force": false}
+	if cmd.Flags().Lookup("no-deps") != nil {
+		for name := range taskFlags {
+			taskFlags[name], err = cmd.Flags().GetBool(name)
+			if err != nil {
+				return nil, fmt.Errorf("error getting value of '%s' flag: %w", name, err)
+			}
+		}
+	}
+
+	// Only the commands that run a session have these flags (see `addSessionFlags`)
+	sessionFlags := map[string]string{"web": "", "metrics": "", "report-json": "", "report-junit": ""}
+	if cmd.Flags().Lookup("web") != nil {
+		for name := range sessionFlags {
+			sessionFlags[name], err = cmd.Flags().GetString(name)
+			if err != nil {
+				return nil, fmt.Errorf("error getting value of '%s' flag: %w", name, err)
+			}
+		}
+	}
+
+	app, err := falcula.NewApp(&falcula.Options{
+		RawMode:        rawMode,
+		KeepLog:        keepLog,
+		WebAddress:     sessionFlags["web"],
+		MetricsAddress: sessionFlags["metrics"],
+		ReportJSON:     sessionFlags["report-json"],
+		ReportJUnit:    sessionFlags["report-junit"],
+		DryRun:         dryRun,
+		NoDeps:         taskFlags["no-deps"],
+		Force:          taskFlags["force"],
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error creating app: %w", err)
 	}
 
 	return app, nil
 }
+
+// addSessionFlags adds the flags of the commands that run a session with `runSession`
+func addSessionFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("detach", false, "Run the session in a background daemon (use 'falcula attach' to open its TUI)")
+	cmd.Flags().String("web", "", "Serve a web dashboard of the session on this address (e.g. ':7777'). A port without host only accepts local connections")
+	cmd.Flags().String("metrics", "", "Serve Prometheus metrics of the services on this address (e.g. ':9090'). A port without host only accepts local connections")
+	cmd.Flags().String("report-json", "", "Write the final state of the services to this JSON file")
+	cmd.Flags().String("report-junit", "", "Write the final state of the services to this JUnit XML file")
+	cmd.Flags().Bool("dry-run", false, "Show the commands that the services would execute instead of executing them (implies --raw)")
+}
+
+// runSession runs a session (script or task) with a new app. If the 'detach' flag is set, starts the session in a background daemon
+// instead, and returns when the session is running
+func runSession(cmd *cobra.Command, run func(app *falcula.App) error) error {
+	cmd.SilenceUsage = true // The errors of the session are not usage errors
+
+	detach, err := cmd.Flags().GetBool("detach")
+	if err != nil {
+		return fmt.Errorf("error getting value of 'detach' flag: %w", err)
+	}
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return fmt.Errorf("error getting value of 'dry-run' flag: %w", err)
+	}
+
+	if detach && dryRun {
+		return errors.New("a dry run can not be detached")
+	}
+
+	// The daemon runs this same command. It must run the session instead of starting another daemon
+	if detach && !falcula.IsDaemon() {
+		session, err := falcula.StartDaemon()
+		if err != nil {
+			return fmt.Errorf("error starting detached session: %w", err)
+		}
+
+		fmt.Printf("Session '%s' is running in the background. Use 'falcula attach %s' to open its TUI\n", session, session)
+		return nil
+	}
+
+	app, err := createFalculaApp(cmd)
+	if err != nil {
+		err = fmt.Errorf("error creating falcula app: %w", err)
+	} else {
+		err = run(app)
+	}
+
+	if err != nil && falcula.IsDaemon() {
+		falcula.NotifyDaemonError(err)
+	}
+
+	return err
+}
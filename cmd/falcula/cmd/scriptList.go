@@ -2,6 +2,12 @@ package cmd
 
 import (
 	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 )
@@ -10,26 +16,49 @@ import (
 var scriptListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List available scripts",
-	Long:  `List available scripts of the current or specified project and its child projects.`,
-	Args:  cobra.NoArgs,
+	Long: `List available scripts of the current or specified project and its child projects, and the configuration file where each script is
+defined (the scripts of the parent configurations are inherited until a configuration with 'root: true'). The scripts that can not run on
+the current platform ('platforms' in the project file) are hidden unless '--all' is provided.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		app, err := createFalculaApp(cmd)
 		if err != nil {
 			return fmt.Errorf("error creating falcula app: %w", err)
 		}
 
-		scripts, err := app.GetScriptList()
+		all, err := cmd.Flags().GetBool("all")
+		if err != nil {
+			return fmt.Errorf("error getting value of 'all' flag: %w", err)
+		}
+
+		scripts, err := app.GetScriptList(all)
 		if err != nil {
 			return fmt.Errorf("error getting scripts list: %w", err)
 		}
-		for name := range scripts {
-			fmt.Println(name)
+
+		currentDir, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("error getting current working directory: %w", err)
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "SCRIPT\tDEFINED IN")
+		for _, name := range slices.Sorted(maps.Keys(scripts)) {
+			// The names of the child project scripts have the `project:script` syntax
+			localName := name[strings.LastIndex(name, ":")+1:]
+			origin := scripts[name].Project.GetScriptOrigin(localName)
+			if relative, err := filepath.Rel(currentDir, origin); err == nil {
+				origin = relative
+			}
+
+			fmt.Fprintf(writer, "%s\t%s\n", name, origin)
 		}
 
-		return nil
+		return writer.Flush()
 	},
 }
 
 func init() {
 	scriptCmd.AddCommand(scriptListCmd)
+	scriptListCmd.Flags().Bool("all", false, "Also list the scripts that can not run on the current platform")
 }
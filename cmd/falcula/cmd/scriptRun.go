@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/LucasAVasco/falcula"
 	"github.com/spf13/cobra"
 )
 
@@ -14,6 +15,15 @@ var scriptRunCmd = &cobra.Command{
 
 The provided arguments are passed to the script.
 
+With '--detach', the script runs in a background daemon that keeps running after the terminal is closed. Use 'falcula attach' to open its
+TUI, 'falcula ctl' to control it and 'falcula ctl shutdown' to stop it.
+
+With '--web <address>', the session also serves a web dashboard with the services, their status and logs (e.g. '--web :7777' serves it on
+http://localhost:7777). It only accepts connections from the local machine, unless a host is provided (e.g. '0.0.0.0:7777').
+
+With '--all-projects', the script runs in every child project (recursively) that defines it, in parallel and without TUI (see 'falcula
+task run --help').
+
 You can access inner project scripts using the following syntax for the script: "innerProject1:innerProject2:script"
 `,
 
@@ -24,26 +34,60 @@ falcula script run scriptName arg1 arg2
 
 falcula script run innerProject1:innerProject2:scriptName
 
-falcula script run innerProject1:innerProject2:scriptName arg1 arg2`,
+falcula script run innerProject1:innerProject2:scriptName arg1 arg2
+
+falcula run scriptName --detach
+
+falcula run scriptName --web :7777`,
 
 	Args: cobra.MinimumNArgs(1),
 
 	RunE: func(cmd *cobra.Command, args []string) error {
-		app, err := createFalculaApp(cmd)
+		runArgs, help, err := parseRunArgs(cmd, args[1:])
 		if err != nil {
-			return fmt.Errorf("error creating falcula app: %w", err)
+			return err
+		}
+
+		if help {
+			app, err := createFalculaApp(cmd)
+			if err != nil {
+				return fmt.Errorf("error creating falcula app: %w", err)
+			}
+
+			params, err := app.GetScriptParams(args[0])
+			if err != nil {
+				return fmt.Errorf("error getting parameters of script '%s': %w", args[0], err)
+			}
+
+			return printRunHelp(cmd, args[0], params)
 		}
 
-		err = app.RunScript(args[0], args[1:]...)
+		allProjectsOpts, err := getAllProjectsOptions(cmd)
 		if err != nil {
-			return fmt.Errorf("error running script: %w", err)
+			return err
 		}
 
-		return nil
+		if allProjectsOpts != nil {
+			return runInAllProjects(cmd, func(app *falcula.App) error {
+				return app.RunScriptInAllProjects(args[0], runArgs, allProjectsOpts)
+			})
+		}
+
+		return runSession(cmd, func(app *falcula.App) error {
+			err := app.RunScript(args[0], runArgs...)
+			if err != nil {
+				return fmt.Errorf("error running script: %w", err)
+			}
+
+			return nil
+		})
 	},
 }
 
 func init() {
 	scriptCmd.AddCommand(scriptRunCmd)
-	rootCmd.AddCommand(scriptRunCmd) // Alias to run scripts with `falcula run <script>`
+	addSessionFlags(scriptRunCmd)
+	addAllProjectsFlags(scriptRunCmd)
+	scriptRunCmd.Flags().SetInterspersed(false) // The flags after the script name are parsed by `parseRunArgs`
+	rootCmd.AddCommand(scriptRunCmd)            // Alias to run scripts with `falcula run <script>`
 }
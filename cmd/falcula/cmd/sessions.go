@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/LucasAVasco/falcula/control"
+	"github.com/spf13/cobra"
+)
+
+// sessionsCmd represents the sessions command
+var sessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "List running sessions",
+	Long: `List the running sessions (scripts and tasks), from the oldest to the newest. Including the detached ones. Use 'falcula attach' to
+open the TUI of a session and 'falcula ctl' to control it.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sockets, err := control.ListSockets()
+		if err != nil {
+			return fmt.Errorf("error listing sessions: %w", err)
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "SESSION\tSTARTED\tPID\tDETACHED\tCOMMAND")
+		for _, socket := range sockets {
+			info, err := getSessionInfo(socket)
+			if err != nil {
+				fmt.Fprintf(writer, "%s\t%s\t-\t-\t(%s)\n", socket.Session, socket.ModTime.Format(time.DateTime), err)
+				continue
+			}
+
+			command := strings.Join(append([]string{info.Command}, info.Args...), " ")
+			fmt.Fprintf(writer, "%s\t%s\t%d\t%t\t%s\n", info.Session, info.StartTime.Format(time.DateTime), info.Pid, info.Detached, command)
+		}
+
+		return writer.Flush()
+	},
+}
+
+// getSessionInfo gets the information of the session that listens on a socket
+func getSessionInfo(socket *control.Socket) (*control.SessionInfo, error) {
+	client, err := control.Dial(socket.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	info := control.SessionInfo{}
+	err = client.Call(control.MethodInfo, nil, &info)
+	if err != nil {
+		return nil, fmt.Errorf("error getting session information: %w", err)
+	}
+
+	return &info, nil
+}
+
+func init() {
+	rootCmd.AddCommand(sessionsCmd)
+}
@@ -10,15 +10,21 @@ import (
 var taskListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List available tasks",
-	Long:  `List available tasks of the current or specified project and its child projects.`,
-	Args:  cobra.NoArgs,
+	Long: `List available tasks of the current or specified project and its child projects. The tasks that can not run on the current platform
+('platforms' in the project file) are hidden unless '--all' is provided.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		app, err := createFalculaApp(cmd)
 		if err != nil {
 			return fmt.Errorf("error creating falcula app: %w", err)
 		}
 
-		tasks, err := app.GetTaskList()
+		all, err := cmd.Flags().GetBool("all")
+		if err != nil {
+			return fmt.Errorf("error getting value of 'all' flag: %w", err)
+		}
+
+		tasks, err := app.GetTaskList(all)
 		if err != nil {
 			return fmt.Errorf("error getting tasks list: %w", err)
 		}
@@ -32,4 +38,5 @@ var taskListCmd = &cobra.Command{
 
 func init() {
 	taskCmd.AddCommand(taskListCmd)
+	taskListCmd.Flags().Bool("all", false, "Also list the tasks that can not run on the current platform")
 }
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/LucasAVasco/falcula"
 	"github.com/spf13/cobra"
 )
 
@@ -17,6 +18,18 @@ have its own subtasks. For example: "taskName.subTaskName.subSubTaskName".
 
 The provided arguments are passed to the task.
 
+With '--detach', the task runs in a background daemon that keeps running after the terminal is closed. With '--web <address>', the session
+serves a web dashboard (see 'falcula script run --help').
+
+The dependencies of the task ('deps' in the project file) run before it, in parallel when they do not depend on each other. Each task runs
+once, even if multiple tasks depend on it. Use '--no-deps' to skip them.
+
+A task with 'sources' is skipped if its source files, generated files ('generates'), definition and arguments did not change since its
+last successful run. Use '--force' to run it anyway (also forces the dependencies) and 'falcula task status' to show the stale tasks.
+
+With '--all-projects', the task runs in every child project (recursively) that defines it, in parallel (see '--concurrency'). The output
+is prefixed by the project name and a summary is shown at the end. Use '--projects <glob>' to only run in the matching projects.
+
 You can access inner project tasks using the following syntax for the task: "innerProject1:innerProject2:taskName"
 `,
 
@@ -33,25 +46,61 @@ falcula task run innerProject1:innerProject2:taskName
 
 falcula task run innerProject1:innerProject2:taskName.subTask
 
-falcula task run innerProject1:innerProject2:taskName arg1 arg2`,
+falcula task run innerProject1:innerProject2:taskName arg1 arg2
+
+falcula task run --all-projects test
+
+falcula task run test --projects 'packages*' --concurrency 4`,
 
 	Args: cobra.MinimumNArgs(1),
 
 	RunE: func(cmd *cobra.Command, args []string) error {
-		app, err := createFalculaApp(cmd)
+		runArgs, help, err := parseRunArgs(cmd, args[1:])
 		if err != nil {
-			return fmt.Errorf("error creating falcula app: %w", err)
+			return err
 		}
 
-		err = app.RunTask(args[0], args[1:]...)
+		if help {
+			app, err := createFalculaApp(cmd)
+			if err != nil {
+				return fmt.Errorf("error creating falcula app: %w", err)
+			}
+
+			params, err := app.GetTaskParams(args[0])
+			if err != nil {
+				return fmt.Errorf("error getting parameters of task '%s': %w", args[0], err)
+			}
+
+			return printRunHelp(cmd, args[0], params)
+		}
+
+		allProjectsOpts, err := getAllProjectsOptions(cmd)
 		if err != nil {
-			return fmt.Errorf("error running task: %w", err)
+			return err
 		}
 
-		return nil
+		if allProjectsOpts != nil {
+			return runInAllProjects(cmd, func(app *falcula.App) error {
+				return app.RunTaskInAllProjects(args[0], runArgs, allProjectsOpts)
+			})
+		}
+
+		return runSession(cmd, func(app *falcula.App) error {
+			err := app.RunTask(args[0], runArgs...)
+			if err != nil {
+				return fmt.Errorf("error running task: %w", err)
+			}
+
+			return nil
+		})
 	},
 }
 
 func init() {
 	taskCmd.AddCommand(taskRunCmd)
+	addSessionFlags(taskRunCmd)
+	addAllProjectsFlags(taskRunCmd)
+	taskRunCmd.Flags().SetInterspersed(false) // The flags after the task name are parsed by `parseRunArgs`
+	taskRunCmd.Flags().Bool("no-deps", false, "Do not run the dependencies of the task")
+	taskRunCmd.Flags().Bool("force", false, "Run the tasks even if they are up to date")
 }
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/LucasAVasco/falcula/fingerprint"
+	"github.com/spf13/cobra"
+)
+
+// taskStatusCmd represents the taskStatus command
+var taskStatusCmd = &cobra.Command{
+	Use:   "status [tasks...]",
+	Short: "Show which tasks are up to date",
+	Long: `Show if the tasks of the current project and its child projects are up to date or stale (and why). Only the tasks with 'sources'
+are checked, the other tasks always run. The tasks are checked without arguments.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		app, err := createFalculaApp(cmd)
+		if err != nil {
+			return fmt.Errorf("error creating falcula app: %w", err)
+		}
+
+		statuses, err := app.GetTaskStatuses(args...)
+		if err != nil {
+			return fmt.Errorf("error getting status of the tasks: %w", err)
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "TASK\tSTATUS")
+		for _, status := range statuses {
+			switch status.Status {
+			case "":
+				fmt.Fprintf(writer, "%s\talways runs\n", status.Name)
+			case fingerprint.StatusUpToDate:
+				fmt.Fprintf(writer, "%s\t%s\n", status.Name, status.Status)
+			default:
+				fmt.Fprintf(writer, "%s\tstale (%s)\n", status.Name, status.Status)
+			}
+		}
+
+		return writer.Flush()
+	},
+}
+
+func init() {
+	taskCmd.AddCommand(taskStatusCmd)
+}
@@ -1,8 +1,18 @@
 // Package main is the main package of the Falcula CLI
 package main
 
-import "github.com/LucasAVasco/falcula/cmd/falcula/cmd"
+import (
+	"os"
+
+	"github.com/LucasAVasco/falcula/cmd/falcula/cmd"
+	"github.com/LucasAVasco/falcula/process"
+)
 
 func main() {
+	// Falcula runs itself to apply the resource limits of a process before executing it
+	if len(os.Args) > 1 && os.Args[1] == process.LimitsHelperArg {
+		process.RunLimitsHelper(os.Args[2:])
+	}
+
 	cmd.Execute()
 }
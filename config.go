@@ -0,0 +1,24 @@
+package falcula
+
+import "fmt"
+
+// RenderConfig returns the configuration of the current project (or of the child project with the provided name) as YAML, with the
+// resolved variables and templates
+func (a *App) RenderConfig(projectName string) ([]byte, error) {
+	config := a.project
+	if projectName != "" {
+		var err error
+		config, err = a.project.GetChildProjectByName(projectName)
+		if err != nil {
+			return nil, fmt.Errorf("error getting child project '%s': %w", projectName, err)
+		}
+	}
+
+	return config.Render()
+}
+
+// ValidateConfig returns the problems of the files and projects referenced by the configuration of the current project and its child
+// projects (see `project.Config.CheckReferences`). The configuration itself is validated when the app is created
+func (a *App) ValidateConfig() []error {
+	return a.project.CheckReferences()
+}
@@ -0,0 +1,131 @@
+package falcula
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/LucasAVasco/falcula/control"
+	"github.com/LucasAVasco/falcula/logfile"
+	"github.com/LucasAVasco/falcula/lua/luaruntime"
+	"github.com/LucasAVasco/falcula/lua/modules/modtui"
+)
+
+// startControlServer starts the control server of the session. The session name is the name of the session log file. The command is the
+// script or task that the session runs (e.g. 'script dev').
+//
+// Errors are logged and do not stop the session, so the returned server can be nil. In a daemon, the server is required to control the
+// session, so errors are returned. The daemon sends its debug logs to the clients of the server and notifies the parent process when the
+// server is running
+func (a *App) startControlServer(runtime *luaruntime.Runtime, command string) (*control.Server, error) {
+	session := strings.TrimSuffix(filepath.Base(runtime.Logger.GetLogFilePath()), logfile.Extension)
+
+	server, err := a.newControlServer(runtime, session, command)
+	if err != nil {
+		if a.detached {
+			notifyDaemonParent("", err)
+			return nil, err
+		}
+
+		runtime.Logger.LogError(err)
+		return nil, nil
+	}
+
+	if a.detached {
+		runtime.Logger.SetOnServiceLog(func(b []byte) (int, error) { return len(b), nil })
+		runtime.Logger.SetOnDebugLog(func(b []byte) (int, error) {
+			server.LogDebug(string(b))
+			return len(b), nil
+		})
+		runtime.Logger.SetOnErrorLog(func(err error) (int, error) {
+			server.LogError(err)
+			return 0, nil
+		})
+
+		a.handleDaemonSignals(runtime)
+		notifyDaemonParent(session, nil)
+	}
+
+	return server, nil
+}
+
+func (a *App) newControlServer(runtime *luaruntime.Runtime, session string, command string) (*control.Server, error) {
+	socketPath, err := control.GetSocketPath(session)
+	if err != nil {
+		return nil, fmt.Errorf("error getting control socket path: %w", err)
+	}
+
+	server, err := control.NewServer(&control.ServerOptions{
+		Path:       socketPath,
+		Runtime:    runtime,
+		OnRerun:    a.rerunScript,
+		OnShutdown: func() error { return a.shutdown(runtime) },
+		Command:    command,
+		Detached:   a.detached,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error starting control server: %w", err)
+	}
+
+	return server, nil
+}
+
+// handleDaemonSignals shuts down the session when the daemon receives a termination signal
+func (a *App) handleDaemonSignals(runtime *luaruntime.Runtime) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+
+	go func() {
+		for range signals {
+			err := a.shutdown(runtime)
+			if err != nil {
+				runtime.Logger.LogError(fmt.Errorf("error shutting down session: %w", err))
+			}
+		}
+	}()
+}
+
+// shutdown ends the session. Stops the services, so the script can return, and hides the TUI
+func (a *App) shutdown(runtime *luaruntime.Runtime) error {
+	a.shuttingDown.Store(true)
+
+	modtui.HidePersistentTui()
+	runtime.CloseAllManagers()
+
+	return nil
+}
+
+// keepSessionRunning checks if the session must keep running after the script returns. The session runs while the TUI is visible. A
+// daemon runs until it is shut down
+func (a *App) keepSessionRunning() bool {
+	if a.detached {
+		return !a.shuttingDown.Load()
+	}
+
+	return modtui.TuiIsVisible()
+}
+
+// setOnRerunScript sets the function that re-runs the current script with new arguments. Can be nil if the script can not be re-run
+func (a *App) setOnRerunScript(onRerun func(args []string) error) {
+	a.rerunMutex.Lock()
+	defer a.rerunMutex.Unlock()
+
+	a.onRerunScript = onRerun
+}
+
+// rerunScript re-runs the current script with new arguments
+func (a *App) rerunScript(args []string) error {
+	a.rerunMutex.Lock()
+	onRerun := a.onRerunScript
+	a.rerunMutex.Unlock()
+
+	if onRerun == nil {
+		return errors.New("the session is not running a Lua file script")
+	}
+
+	return onRerun(args)
+}
@@ -0,0 +1,130 @@
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+)
+
+// NotificationHandler handles a notification received while waiting for the response of a request
+type NotificationHandler func(method string, params json.RawMessage) error
+
+// message is a message received by the client. Can be a response or a notification
+type message struct {
+	Version string          `json:"jsonrpc"`
+	Id      *int64          `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Client is a client of the control server of a session
+type Client struct {
+	mutex   sync.Mutex // Only one request at a time
+	conn    net.Conn
+	scanner *bufio.Scanner
+	encoder *json.Encoder
+	lastId  int64
+}
+
+// Dial connects to the control server listening on a socket
+func Dial(socketPath string) (*Client, error) {
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to socket '%s': %w", socketPath, err)
+	}
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 4096), maxMessageSize)
+
+	return &Client{
+		conn:    conn,
+		scanner: scanner,
+		encoder: json.NewEncoder(conn),
+	}, nil
+}
+
+// Close closes the connection. Interrupts the current request
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Call sends a request and decodes its result. The params and the result are optional (can be nil)
+func (c *Client) Call(method string, params any, result any) error {
+	return c.Stream(method, params, result, nil)
+}
+
+// Stream sends a request and calls the handler for each notification received before the response. The params, the result and the handler
+// are optional (can be nil)
+func (c *Client) Stream(method string, params any, result any, handler NotificationHandler) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.lastId++
+	id := c.lastId
+
+	request := Request{
+		Version: Version,
+		Id:      &id,
+		Method:  method,
+	}
+
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("error encoding parameters: %w", err)
+		}
+		request.Params = data
+	}
+
+	err := c.encoder.Encode(&request)
+	if err != nil {
+		return fmt.Errorf("error sending request: %w", err)
+	}
+
+	for c.scanner.Scan() {
+		msg := message{}
+		err := json.Unmarshal(c.scanner.Bytes(), &msg)
+		if err != nil {
+			return fmt.Errorf("error decoding message: %w", err)
+		}
+
+		// Notification
+		if msg.Method != "" {
+			if handler != nil {
+				err := handler(msg.Method, msg.Params)
+				if err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		// Response
+		if msg.Id == nil || *msg.Id != id {
+			continue
+		}
+
+		if msg.Error != nil {
+			return msg.Error
+		}
+
+		if result != nil && len(msg.Result) > 0 {
+			err := json.Unmarshal(msg.Result, result)
+			if err != nil {
+				return fmt.Errorf("error decoding result: %w", err)
+			}
+		}
+
+		return nil
+	}
+
+	if err := c.scanner.Err(); err != nil {
+		return fmt.Errorf("error reading response: %w", err)
+	}
+
+	return fmt.Errorf("connection closed by the server")
+}
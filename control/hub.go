@@ -0,0 +1,105 @@
+package control
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/LucasAVasco/falcula/logsink"
+)
+
+// subscriberQueueSize is the number of entries that can wait to be sent to a subscriber. New entries are dropped if the queue is full
+const subscriberQueueSize = 1024
+
+// Streams of the debug entries
+const (
+	DebugStream = "debug"
+	ErrorStream = "error"
+)
+
+// hub is a log sink that keeps the last service logs and sends the new ones to the subscribers. It also keeps the debug messages of
+// Falcula, as entries without service
+type hub struct {
+	mutex       sync.Mutex
+	ring        *logsink.Ring
+	subscribers map[chan logsink.Entry]struct{}
+	closed      bool
+}
+
+func newHub(size int) *hub {
+	return &hub{
+		ring:        logsink.NewRing(size),
+		subscribers: make(map[chan logsink.Entry]struct{}),
+	}
+}
+
+func (h *hub) Write(entry *logsink.Entry) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.ring.Write(entry)
+	for subscriber := range h.subscribers {
+		select {
+		case subscriber <- *entry:
+		default: // Slow subscriber
+		}
+	}
+
+	return nil
+}
+
+// writeDebug adds a debug message. The message must end with a newline
+func (h *hub) writeDebug(stream string, message string) {
+	h.Write(&logsink.Entry{
+		Time:    time.Now(),
+		Stream:  stream,
+		Message: strings.TrimSuffix(message, "\n"),
+		UILine:  message,
+	})
+}
+
+// isDebugEntry checks if an entry is a debug message of Falcula instead of a service log
+func isDebugEntry(entry *logsink.Entry) bool {
+	return entry.Service == ""
+}
+
+// Close closes the channels of all subscribers
+func (h *hub) Close() error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for subscriber := range h.subscribers {
+		close(subscriber)
+	}
+	clear(h.subscribers)
+	h.closed = true
+
+	return nil
+}
+
+// subscribe returns the buffered entries and a channel that receives the new ones. The channel is closed when the hub is closed. The
+// subscriber must call `unsubscribe` when done
+func (h *hub) subscribe() ([]logsink.Entry, chan logsink.Entry) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	subscriber := make(chan logsink.Entry, subscriberQueueSize)
+	if h.closed {
+		close(subscriber)
+	} else {
+		h.subscribers[subscriber] = struct{}{}
+	}
+
+	return h.ring.GetEntries(), subscriber
+}
+
+// unsubscribe removes a subscriber and closes its channel
+func (h *hub) unsubscribe(subscriber chan logsink.Entry) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if _, ok := h.subscribers[subscriber]; ok {
+		delete(h.subscribers, subscriber)
+		close(subscriber)
+	}
+}
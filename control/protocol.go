@@ -0,0 +1,148 @@
+// Package control implements a JSON-RPC 2.0 API to control a running session over a Unix socket, and a client to use it.
+//
+// Each message is a JSON object in a line. A connection can send multiple requests, one after the other. Methods that stream data (e.g.
+// following the logs) send notifications before the response
+package control
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/LucasAVasco/falcula/logsink"
+	"github.com/LucasAVasco/falcula/service/enhanced"
+)
+
+// Version is the JSON-RPC version
+const Version = "2.0"
+
+// Methods supported by the server
+const (
+	MethodList     = "list"     // Lists the managers and their services. No parameters. Returns []ManagerInfo
+	MethodStatus   = "status"   // Status of the target services. Parameters: TargetParams (optional target). Returns []ServiceInfo
+	MethodStart    = "start"    // Starts (prepares and starts) the target services. Parameters: TargetParams
+	MethodStop     = "stop"     // Stops the target services. Parameters: TargetParams
+	MethodRestart  = "restart"  // Restarts the target services. Parameters: TargetParams
+	MethodEnable   = "enable"   // Enables the target services. Parameters: TargetParams
+	MethodDisable  = "disable"  // Disables the target services. Parameters: TargetParams
+	MethodLogs     = "logs"     // Sends the service logs as `NotificationLog` notifications. Parameters: LogsParams
+	MethodRerun    = "rerun"    // Re-runs the script with new arguments. Parameters: RerunParams
+	MethodInfo     = "info"     // Information about the session. No parameters. Returns SessionInfo
+	MethodArgs     = "args"     // Current and available arguments of the script. No parameters. Returns ArgsInfo
+	MethodShutdown = "shutdown" // Stops the services and ends the session. No parameters
+)
+
+// Notifications sent by the server
+const (
+	NotificationLog   = "log"   // Service log line. Parameters: LogEntry
+	NotificationDebug = "debug" // Debug or error message of Falcula (only sent by detached sessions). Parameters: LogEntry
+)
+
+// Error codes
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// Request is a JSON-RPC request. Notifications sent by the server have no ID
+type Request struct {
+	Version string          `json:"jsonrpc"`
+	Id      *int64          `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is a JSON-RPC response
+type Response struct {
+	Version string          `json:"jsonrpc"`
+	Id      *int64          `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC error
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// TargetParams selects services. The target is the name of a service, the name of a manager (all its services) or '<manager>/<service>'.
+// All services are selected if the target is empty
+type TargetParams struct {
+	Target string `json:"target"`
+	Force  bool   `json:"force,omitempty"` // Force the stop instead of a graceful shutdown (stop and restart)
+}
+
+// LogsParams selects the logs to send
+type LogsParams struct {
+	Service string `json:"service,omitempty"` // Glob matched against the service name. All services if empty
+	Lines   int    `json:"lines,omitempty"`   // Number of previous lines to send. Negative to send all buffered lines
+	Follow  bool   `json:"follow,omitempty"`  // Keep sending the new lines until the connection is closed
+	Debug   bool   `json:"debug,omitempty"`   // Also send the debug messages as `NotificationDebug` notifications
+}
+
+// RerunParams are the new arguments of the script
+type RerunParams struct {
+	Args []string `json:"args"`
+}
+
+// ManagerInfo is a manager and its services
+type ManagerInfo struct {
+	Name     string         `json:"name"`
+	Services []*ServiceInfo `json:"services"`
+}
+
+// ServiceInfo is the state of a service
+type ServiceInfo struct {
+	Manager  string            `json:"manager"`
+	Name     string            `json:"name"`
+	Status   string            `json:"status"`
+	Ready    bool              `json:"ready"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Usage    []enhanced.Usage  `json:"usage,omitempty"` // Resource usage samples of the running service, from the oldest to the newest
+}
+
+// SessionInfo is the information about a session
+type SessionInfo struct {
+	Session   string    `json:"session"`
+	Pid       int       `json:"pid"`
+	Command   string    `json:"command"` // Script or task that the session runs (e.g. 'script dev')
+	Args      []string  `json:"args"`    // Current arguments of the script
+	Detached  bool      `json:"detached"`
+	LogFile   string    `json:"log_file"`
+	StartTime time.Time `json:"start_time"`
+}
+
+// ArgsInfo is the current and the available arguments of the script
+type ArgsInfo struct {
+	Current   []string   `json:"current"`
+	Available [][]string `json:"available"`
+}
+
+// LogEntry is a service log line
+type LogEntry struct {
+	Time    time.Time `json:"time"`
+	Service string    `json:"service"`
+	Stream  string    `json:"stream"`
+	Level   string    `json:"level,omitempty"`
+	Message string    `json:"message"`
+	Line    string    `json:"line,omitempty"` // Entry formatted to be shown in the UI (with ANSI colors and the trailing newline)
+}
+
+// ToSinkEntry converts the log entry to a log sink entry
+func (e *LogEntry) ToSinkEntry() *logsink.Entry {
+	return &logsink.Entry{
+		Time:    e.Time,
+		Service: e.Service,
+		Stream:  e.Stream,
+		Level:   e.Level,
+		Message: e.Message,
+		UILine:  e.Line,
+	}
+}
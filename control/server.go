@@ -0,0 +1,443 @@
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/LucasAVasco/falcula/logsink"
+	"github.com/LucasAVasco/falcula/lua/luaruntime"
+)
+
+// maxMessageSize is the maximum size of a message received by the server
+const maxMessageSize = 1024 * 1024
+
+// closeTimeout is the maximum time to send the pending responses when closing the server
+const closeTimeout = time.Second
+
+// ServerOptions is the server options
+type ServerOptions struct {
+	Path          string                    // Path of the Unix socket. Required
+	Runtime       *luaruntime.Runtime       // Runtime with the managers and the logger. Required
+	OnRerun       func(args []string) error // Re-runs the script with new arguments. The 'rerun' method fails if it is nil
+	OnShutdown    func() error              // Ends the session. The 'shutdown' method fails if it is nil
+	LogBufferSize int                       // Number of log lines kept to send to new clients. Uses `logsink.DefaultRingSize` if 0
+	Command       string                    // Script or task that the session runs. Only informative
+	Detached      bool                      // The session runs in a background daemon. Only informative
+}
+
+// Server is the control server of a session. It listens on a Unix socket and handles the JSON-RPC requests of the clients
+type Server struct {
+	opts      ServerOptions
+	listener  net.Listener
+	hub       *hub // Service logs and debug messages sent to the clients
+	startTime time.Time
+
+	connsMutex sync.Mutex
+	conns      map[net.Conn]struct{}
+	closed     bool
+	waitGroup  sync.WaitGroup
+}
+
+// NewServer creates a server and starts listening on its socket
+func NewServer(opts *ServerOptions) (*Server, error) {
+	err := os.MkdirAll(filepath.Dir(opts.Path), 0700)
+	if err != nil {
+		return nil, fmt.Errorf("error creating socket directory: %w", err)
+	}
+
+	listener, err := net.Listen("unix", opts.Path)
+	if err != nil {
+		return nil, fmt.Errorf("error listening on socket '%s': %w", opts.Path, err)
+	}
+
+	s := &Server{
+		opts:      *opts,
+		listener:  listener,
+		hub:       newHub(opts.LogBufferSize),
+		startTime: time.Now(),
+		conns:     make(map[net.Conn]struct{}),
+	}
+
+	err = s.opts.Runtime.Logger.AddSink("", s.hub)
+	if err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("error adding log sink of control server: %w", err)
+	}
+
+	s.waitGroup.Go(s.accept)
+
+	return s, nil
+}
+
+// GetPath returns the path of the socket
+func (s *Server) GetPath() string {
+	return s.opts.Path
+}
+
+// GetSession returns the name of the session (the name of the socket without the extension)
+func (s *Server) GetSession() string {
+	return strings.TrimSuffix(filepath.Base(s.opts.Path), SocketExtension)
+}
+
+// LogDebug sends a debug message to the clients that follow the logs with debug messages
+func (s *Server) LogDebug(message string) {
+	s.hub.writeDebug(DebugStream, message)
+}
+
+// LogError sends an error to the clients that follow the logs with debug messages
+func (s *Server) LogError(err error) {
+	s.hub.writeDebug(ErrorStream, err.Error()+"\n")
+}
+
+// Close stops the server, closes the connections and removes the socket. Can be called multiple times
+func (s *Server) Close() error {
+	s.connsMutex.Lock()
+	if s.closed {
+		s.connsMutex.Unlock()
+		return nil
+	}
+	s.closed = true
+
+	err := s.listener.Close() // Also removes the socket file
+
+	// Stops reading new requests, but lets the current requests send their responses
+	for conn := range s.conns {
+		conn.SetWriteDeadline(time.Now().Add(closeTimeout))
+		conn.(*net.UnixConn).CloseRead()
+	}
+	s.connsMutex.Unlock()
+
+	// Ends the log streams
+	if s.opts.Runtime.Logger.RemoveSink(s.hub) != nil {
+		s.hub.Close()
+	}
+
+	s.waitGroup.Wait()
+
+	if err != nil {
+		return fmt.Errorf("error closing listener: %w", err)
+	}
+
+	return nil
+}
+
+// accept accepts the connections until the listener is closed
+func (s *Server) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.connsMutex.Lock()
+		if s.closed {
+			s.connsMutex.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.connsMutex.Unlock()
+
+		s.waitGroup.Go(func() {
+			s.serve(conn)
+
+			s.connsMutex.Lock()
+			delete(s.conns, conn)
+			s.connsMutex.Unlock()
+			conn.Close()
+		})
+	}
+}
+
+// serve handles the requests of a connection until it is closed
+func (s *Server) serve(conn net.Conn) {
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 4096), maxMessageSize)
+	encoder := json.NewEncoder(conn)
+
+	for scanner.Scan() {
+		request := Request{}
+		err := json.Unmarshal(scanner.Bytes(), &request)
+		if err != nil {
+			encoder.Encode(newErrorResponse(nil, &Error{Code: CodeParseError, Message: "invalid JSON: " + err.Error()}))
+			continue
+		}
+
+		if request.Version != Version || request.Method == "" {
+			encoder.Encode(newErrorResponse(request.Id, &Error{Code: CodeInvalidRequest, Message: "invalid JSON-RPC 2.0 request"}))
+			continue
+		}
+
+		result, rpcErr := s.handle(conn, encoder, &request)
+
+		// Notifications do not have responses
+		if request.Id == nil {
+			continue
+		}
+
+		var response *Response
+		if rpcErr != nil {
+			response = newErrorResponse(request.Id, rpcErr)
+		} else {
+			response, err = newResultResponse(request.Id, result)
+			if err != nil {
+				response = newErrorResponse(request.Id, &Error{Code: CodeInternalError, Message: err.Error()})
+			}
+		}
+
+		err = encoder.Encode(response)
+		if err != nil {
+			return
+		}
+	}
+}
+
+func newErrorResponse(id *int64, rpcErr *Error) *Response {
+	return &Response{
+		Version: Version,
+		Id:      id,
+		Error:   rpcErr,
+	}
+}
+
+func newResultResponse(id *int64, result any) (*Response, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding result: %w", err)
+	}
+
+	return &Response{
+		Version: Version,
+		Id:      id,
+		Result:  data,
+	}, nil
+}
+
+// decodeParams decodes the parameters of a request. Missing parameters are not an error
+func decodeParams(request *Request, params any) *Error {
+	if len(request.Params) == 0 {
+		return nil
+	}
+
+	err := json.Unmarshal(request.Params, params)
+	if err != nil {
+		return &Error{Code: CodeInvalidParams, Message: "invalid parameters: " + err.Error()}
+	}
+
+	return nil
+}
+
+// internalError converts an error to a JSON-RPC error. Returns nil if the error is nil
+func internalError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	return &Error{Code: CodeInternalError, Message: err.Error()}
+}
+
+// handle handles a request and returns its result
+func (s *Server) handle(conn net.Conn, encoder *json.Encoder, request *Request) (any, *Error) {
+	switch request.Method {
+	case MethodList:
+		return ListManagers(s.opts.Runtime), nil
+
+	case MethodStatus:
+		params := TargetParams{}
+		if rpcErr := decodeParams(request, &params); rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		targets, err := selectServices(s.opts.Runtime, params.Target)
+		if err != nil {
+			return nil, internalError(err)
+		}
+
+		infos := make([]*ServiceInfo, 0, len(targets))
+		for _, target := range targets {
+			infos = append(infos, NewServiceInfo(target.manager, target.service))
+		}
+		return infos, nil
+
+	case MethodStart, MethodStop, MethodRestart, MethodEnable, MethodDisable:
+		params := TargetParams{}
+		if rpcErr := decodeParams(request, &params); rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		return nil, internalError(RunAction(s.opts.Runtime, request.Method, &params))
+
+	case MethodLogs:
+		params := LogsParams{}
+		if rpcErr := decodeParams(request, &params); rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		return nil, internalError(s.streamLogs(conn, encoder, &params))
+
+	case MethodRerun:
+		params := RerunParams{}
+		if rpcErr := decodeParams(request, &params); rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		if s.opts.OnRerun == nil {
+			return nil, internalError(errors.New("the session can not re-run its script"))
+		}
+
+		return nil, internalError(s.opts.OnRerun(params.Args))
+
+	case MethodInfo:
+		return &SessionInfo{
+			Session:   s.GetSession(),
+			Pid:       os.Getpid(),
+			Command:   s.opts.Command,
+			Args:      s.opts.Runtime.GetScriptCurrentArgs(),
+			Detached:  s.opts.Detached,
+			LogFile:   s.opts.Runtime.Logger.GetLogFilePath(),
+			StartTime: s.startTime,
+		}, nil
+
+	case MethodArgs:
+		return &ArgsInfo{
+			Current:   s.opts.Runtime.GetScriptCurrentArgs(),
+			Available: s.opts.Runtime.GetScriptAvailableArgs(),
+		}, nil
+
+	case MethodShutdown:
+		if s.opts.OnShutdown == nil {
+			return nil, internalError(errors.New("the session can not be shut down"))
+		}
+
+		return nil, internalError(s.opts.OnShutdown())
+
+	default:
+		return nil, &Error{Code: CodeMethodNotFound, Message: fmt.Sprintf("method '%s' not found", request.Method)}
+	}
+}
+
+// streamLogs sends the service logs selected by the parameters as notifications. A client that follows the logs must not send requests
+// until the stream ends: the connection is read to know when the client disconnects, and the read data is discarded
+func (s *Server) streamLogs(conn net.Conn, encoder *json.Encoder, params *LogsParams) error {
+	if params.Service != "" {
+		_, err := path.Match(params.Service, "")
+		if err != nil {
+			return fmt.Errorf("invalid service glob '%s': %w", params.Service, err)
+		}
+	}
+
+	entries, subscriber := s.hub.subscribe()
+	defer s.hub.unsubscribe(subscriber)
+
+	// Previous lines
+	matching := make([]logsink.Entry, 0, len(entries))
+	for _, entry := range entries {
+		if matchEntry(params, &entry) {
+			matching = append(matching, entry)
+		}
+	}
+
+	if params.Lines >= 0 && len(matching) > params.Lines {
+		matching = matching[len(matching)-params.Lines:]
+	}
+
+	for _, entry := range matching {
+		err := sendLogNotification(encoder, &entry)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !params.Follow {
+		return nil
+	}
+
+	// Disconnection of the client. Otherwise it would only be noticed when sending the next line
+	disconnected := make(chan struct{})
+	go func() {
+		io.Copy(io.Discard, conn)
+		close(disconnected)
+	}()
+
+	defer func() {
+		// Stops reading, so the connection can read the next requests
+		conn.SetReadDeadline(time.Now())
+		<-disconnected
+		conn.SetReadDeadline(time.Time{})
+	}()
+
+	// New lines. The channel is closed when the server is closed
+	for {
+		select {
+		case entry, ok := <-subscriber:
+			if !ok {
+				return nil
+			}
+
+			if !matchEntry(params, &entry) {
+				continue
+			}
+
+			err := sendLogNotification(encoder, &entry)
+			if err != nil {
+				return err
+			}
+
+		case <-disconnected:
+			return nil
+		}
+	}
+}
+
+// matchEntry checks if an entry is selected by the parameters of the 'logs' method
+func matchEntry(params *LogsParams, entry *logsink.Entry) bool {
+	if isDebugEntry(entry) {
+		return params.Debug
+	}
+
+	return matchService(params.Service, entry.Service)
+}
+
+func matchService(glob string, service string) bool {
+	if glob == "" {
+		return true
+	}
+
+	matched, _ := path.Match(glob, service)
+	return matched
+}
+
+func sendLogNotification(encoder *json.Encoder, entry *logsink.Entry) error {
+	params, err := json.Marshal(&LogEntry{
+		Time:    entry.Time,
+		Service: entry.Service,
+		Stream:  entry.Stream,
+		Level:   entry.Level,
+		Message: entry.Message,
+		Line:    entry.UILine,
+	})
+	if err != nil {
+		return fmt.Errorf("error encoding log entry: %w", err)
+	}
+
+	method := NotificationLog
+	if isDebugEntry(entry) {
+		method = NotificationDebug
+	}
+
+	return encoder.Encode(&Request{
+		Version: Version,
+		Method:  method,
+		Params:  params,
+	})
+}
@@ -0,0 +1,135 @@
+package control
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/LucasAVasco/falcula/lua/luaruntime"
+	"github.com/LucasAVasco/falcula/service/enhanced"
+	"github.com/LucasAVasco/falcula/service/manager"
+	"github.com/LucasAVasco/falcula/service/status"
+)
+
+// ListManagers returns the managers of a runtime and their services
+func ListManagers(runtime *luaruntime.Runtime) []*ManagerInfo {
+	managers := runtime.GetManagers()
+	infos := make([]*ManagerInfo, 0, len(managers))
+
+	for _, man := range managers {
+		info := ManagerInfo{
+			Name:     man.GetName(),
+			Services: []*ServiceInfo{},
+		}
+
+		for _, svc := range man.GetServices() {
+			info.Services = append(info.Services, NewServiceInfo(man, svc))
+		}
+
+		infos = append(infos, &info)
+	}
+
+	return infos
+}
+
+// NewServiceInfo returns the information of a service sent to the clients
+func NewServiceInfo(man *manager.Manager, svc *enhanced.EnhancedService) *ServiceInfo {
+	info := ServiceInfo{
+		Manager:  man.GetName(),
+		Name:     svc.GetName(),
+		Status:   svc.GetStatus().ToString(),
+		Ready:    svc.IsReady(),
+		Metadata: svc.GetAllMetadata(),
+	}
+
+	if svc.GetStatus() == status.Running {
+		info.Usage = svc.GetUsageHistory()
+	}
+
+	return &info
+}
+
+// target is a service selected by a request
+type target struct {
+	manager *manager.Manager
+	service *enhanced.EnhancedService
+}
+
+// selectServices returns the services selected by a target (see `TargetParams`)
+func selectServices(runtime *luaruntime.Runtime, name string) ([]target, error) {
+	managerName, serviceName, hasManager := strings.Cut(name, "/")
+	if !hasManager {
+		managerName = ""
+		serviceName = name
+	}
+
+	byService := []target{}
+	byManager := []target{}
+	for _, man := range runtime.GetManagers() {
+		if managerName != "" && man.GetName() != managerName {
+			continue
+		}
+
+		for _, svc := range man.GetServices() {
+			if serviceName == "" || svc.GetName() == serviceName {
+				byService = append(byService, target{manager: man, service: svc})
+			}
+
+			if !hasManager && man.GetName() == name {
+				byManager = append(byManager, target{manager: man, service: svc})
+			}
+		}
+	}
+
+	switch {
+	case len(byService) > 1 && serviceName != "":
+		return nil, fmt.Errorf("the service name '%s' is ambiguous, use '<manager>/%s'", serviceName, serviceName)
+	case len(byService) > 0:
+		return byService, nil
+	case len(byManager) > 0:
+		return byManager, nil
+	default:
+		return nil, fmt.Errorf("there is no service or manager named '%s'", name)
+	}
+}
+
+// RunAction runs an action (`MethodStart`, `MethodStop`, `MethodRestart`, `MethodEnable` or `MethodDisable`) on the services of a runtime
+// selected by the parameters
+func RunAction(runtime *luaruntime.Runtime, action string, params *TargetParams) error {
+	targets, err := selectServices(runtime, params.Target)
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, target := range targets {
+		svc := target.service
+
+		switch action {
+		case MethodStart:
+			switch svcStatus := svc.GetStatus(); {
+			case svcStatus == status.Disabled:
+				err = fmt.Errorf("service '%s' is disabled", svc.GetName())
+			case svcStatus.IsDoingNothing() || svcStatus == status.Error:
+				_, err = svc.Restart(false)
+			default:
+				err = fmt.Errorf("service '%s' is already running (status: %s)", svc.GetName(), svcStatus.ToString())
+			}
+
+		case MethodStop:
+			_, err = svc.AbortPrepareOrStop(params.Force)
+		case MethodRestart:
+			_, err = svc.Restart(params.Force)
+		case MethodEnable:
+			err = svc.Enable()
+		case MethodDisable:
+			err = svc.Disable()
+		}
+
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,147 @@
+package control
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/LucasAVasco/falcula/logfile"
+)
+
+// SocketExtension is the extension of the session sockets
+const SocketExtension = ".sock"
+
+// GetSocketDir returns the directory of the session sockets (the default directory of the session log files)
+func GetSocketDir() (string, error) {
+	return logfile.GetDefaultDir()
+}
+
+// GetSocketPath returns the socket path of a session
+func GetSocketPath(session string) (string, error) {
+	dir, err := GetSocketDir()
+	if err != nil {
+		return "", fmt.Errorf("error getting socket directory: %w", err)
+	}
+
+	return filepath.Join(dir, session+SocketExtension), nil
+}
+
+// IsRunning returns true if the session has a control socket that accepts connections
+func IsRunning(session string) bool {
+	socketPath, err := GetSocketPath(session)
+	if err != nil {
+		return false
+	}
+
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+
+	return true
+}
+
+// Socket is the socket of a running session
+type Socket struct {
+	Session string
+	Path    string
+	ModTime time.Time // Time when the session started
+}
+
+// ListSockets returns the sockets of the running sessions sorted from the oldest to the newest. Removes the sockets of sessions that are
+// not running anymore (e.g. Falcula crashed)
+func ListSockets() ([]*Socket, error) {
+	dir, err := GetSocketDir()
+	if err != nil {
+		return nil, fmt.Errorf("error getting socket directory: %w", err)
+	}
+
+	dirEntries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []*Socket{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading socket directory '%s': %w", dir, err)
+	}
+
+	sockets := make([]*Socket, 0)
+	for _, dirEntry := range dirEntries {
+		session, ok := strings.CutSuffix(dirEntry.Name(), SocketExtension)
+		if !ok || dirEntry.Type()&os.ModeSocket == 0 {
+			continue
+		}
+
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+
+		socket := Socket{
+			Session: session,
+			Path:    filepath.Join(dir, dirEntry.Name()),
+			ModTime: info.ModTime(),
+		}
+
+		// Stale socket. Only removed if the connection is refused: other errors (e.g. a busy session that is starting) do not mean that the
+		// session ended
+		conn, err := net.Dial("unix", socket.Path)
+		if err != nil {
+			if errors.Is(err, syscall.ECONNREFUSED) {
+				os.Remove(socket.Path)
+			}
+			continue
+		}
+		conn.Close()
+
+		sockets = append(sockets, &socket)
+	}
+
+	slices.SortFunc(sockets, func(a, b *Socket) int {
+		return a.ModTime.Compare(b.ModTime)
+	})
+
+	return sockets, nil
+}
+
+// FindSocket returns the socket of the running session with the provided name. The name can be a unique prefix of the session name. If the
+// name is empty, returns the newest session
+func FindSocket(session string) (*Socket, error) {
+	sockets, err := ListSockets()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sockets) == 0 {
+		return nil, fmt.Errorf("there are no running sessions")
+	}
+
+	if session == "" {
+		return sockets[len(sockets)-1], nil
+	}
+
+	var found *Socket
+	for _, socket := range sockets {
+		if socket.Session == session {
+			return socket, nil
+		}
+
+		if strings.HasPrefix(socket.Session, session) {
+			if found != nil {
+				return nil, fmt.Errorf("the session name '%s' is ambiguous", session)
+			}
+			found = socket
+		}
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("running session '%s' not found", session)
+	}
+
+	return found, nil
+}
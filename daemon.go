@@ -0,0 +1,126 @@
+package falcula
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// daemonReadyFdEnv is set in the environment of the daemon process. Its value is the file descriptor used to notify the parent process
+// when the session is running
+const daemonReadyFdEnv = "FALCULA_DAEMON_READY_FD"
+
+// Messages sent by the daemon to the parent process
+const (
+	daemonReadyPrefix = "ready "
+	daemonErrorPrefix = "error "
+)
+
+var daemonNotifyOnce sync.Once
+
+// daemonReadyFd is the value of `daemonReadyFdEnv` when the process started. Empty if the process is not a daemon
+var daemonReadyFd string
+
+// init reads the file descriptor of the daemon and removes it from the environment, so the processes started by the daemon (e.g. services
+// and other Falcula processes) do not inherit it
+func init() {
+	daemonReadyFd = os.Getenv(daemonReadyFdEnv)
+	os.Unsetenv(daemonReadyFdEnv)
+}
+
+// IsDaemon checks if the current process is a daemon started by `StartDaemon`
+func IsDaemon() bool {
+	return daemonReadyFd != ""
+}
+
+// StartDaemon runs the current command (with the same arguments) in a background daemon process, detached from the terminal. Waits until
+// the session of the daemon is running and returns its name. The daemon runs in raw mode until it is shut down (`falcula ctl shutdown`)
+func StartDaemon() (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("error getting path of the falcula executable: %w", err)
+	}
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return "", fmt.Errorf("error creating pipe: %w", err)
+	}
+	defer reader.Close()
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_RDWR, 0)
+	if err != nil {
+		writer.Close()
+		return "", fmt.Errorf("error opening '%s': %w", os.DevNull, err)
+	}
+	defer devNull.Close()
+
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdin = devNull
+	cmd.Stdout = devNull
+	cmd.Stderr = devNull
+	cmd.ExtraFiles = []*os.File{writer} // File descriptor 3
+	cmd.Env = append(os.Environ(), daemonReadyFdEnv+"=3")
+	configureDaemonCmd(cmd)
+
+	err = cmd.Start()
+	writer.Close() // Only the daemon writes to the pipe. Reading it returns EOF if the daemon exits
+	if err != nil {
+		return "", fmt.Errorf("error starting daemon: %w", err)
+	}
+	cmd.Process.Release()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("error reading daemon status: %w", err)
+	}
+
+	message := strings.TrimSpace(string(data))
+	if session, ok := strings.CutPrefix(message, daemonReadyPrefix); ok {
+		return session, nil
+	}
+
+	if errMessage, ok := strings.CutPrefix(message, daemonErrorPrefix); ok {
+		return "", errors.New(errMessage)
+	}
+
+	return "", errors.New("the daemon exited before starting its session")
+}
+
+// notifyDaemonParent sends the session name (or the error that prevented the session from starting) to the process that started the
+// daemon. Only the first call sends a message. Does nothing if the current process is not a daemon
+func notifyDaemonParent(session string, err error) {
+	if !IsDaemon() {
+		return
+	}
+
+	daemonNotifyOnce.Do(func() {
+		var fd uintptr
+		_, scanErr := fmt.Sscan(daemonReadyFd, &fd)
+		if scanErr != nil {
+			return
+		}
+
+		file := os.NewFile(fd, "daemon-ready")
+		defer file.Close()
+
+		if err != nil {
+			fmt.Fprintln(file, daemonErrorPrefix+strings.ReplaceAll(err.Error(), "\n", " "))
+		} else {
+			fmt.Fprintln(file, daemonReadyPrefix+session)
+		}
+	})
+}
+
+// NotifyDaemonError sends an error to the process that started the daemon if the session has not started yet. Does nothing if the current
+// process is not a daemon
+func NotifyDaemonError(err error) {
+	if err == nil {
+		err = errors.New("the daemon exited before starting its session")
+	}
+
+	notifyDaemonParent("", err)
+}
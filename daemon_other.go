@@ -0,0 +1,10 @@
+//go:build !unix
+
+package falcula
+
+import (
+	"os/exec"
+)
+
+// configureDaemonCmd does nothing. The daemon is not detached from the terminal in this platform
+func configureDaemonCmd(cmd *exec.Cmd) {}
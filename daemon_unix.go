@@ -0,0 +1,13 @@
+//go:build unix
+
+package falcula
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// configureDaemonCmd starts the daemon in a new session, so it is not killed when the terminal is closed
+func configureDaemonCmd(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
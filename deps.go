@@ -0,0 +1,235 @@
+package falcula
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/LucasAVasco/falcula/colorgen"
+	"github.com/LucasAVasco/falcula/lua/luaruntime"
+	"github.com/LucasAVasco/falcula/multiplexer"
+	"github.com/LucasAVasco/falcula/project"
+	"github.com/fatih/color"
+)
+
+// taskNode is a task of the dependency graph of the task being run
+type taskNode struct {
+	name    string        // Name shown to the user, relative to the current project (e.g. 'lib:build.release')
+	localId string        // Task ID relative to the project of the task (e.g. 'build.release')
+	task    *project.Task // Task configuration
+	deps    []*taskNode
+
+	done chan struct{} // Closed when the task ends
+	err  error         // Error of the task. Only read after `done` is closed
+	clr  *color.Color  // Color of the output prefix
+}
+
+// getTaskKey returns the key that identifies a task in the dependency graph. A task has the same key regardless of the project that
+// references it, so it only runs once
+func getTaskKey(task *project.Task, localId string) string {
+	return task.Project.Folder + ":" + localId
+}
+
+// taskGraphBuilder builds the dependency graph of a task
+type taskGraphBuilder struct {
+	nodes    map[string]*taskNode // By key (see `getTaskKey`)
+	visiting []string             // Keys of the tasks being visited. Used to detect cycles
+	path     []string             // Names of the tasks being visited. Used to show the cycles
+}
+
+// buildTaskGraph builds the dependency graph of a task. Returns an error if a dependency does not exist or there is a dependency cycle
+func buildTaskGraph(task *project.Task, taskId string) (*taskNode, error) {
+	builder := taskGraphBuilder{
+		nodes: make(map[string]*taskNode),
+	}
+
+	return builder.addTask(task, taskId, getTaskLocalId(taskId))
+}
+
+// getTaskLocalId returns the ID of a task relative to its project. Example: 'lib:build.release' -> 'build.release'
+func getTaskLocalId(taskId string) string {
+	taskName, subTask, hasSubTask := strings.Cut(taskId, ".")
+	_, localId := cutProjectPrefix(taskName)
+	if hasSubTask {
+		localId += "." + subTask
+	}
+
+	return localId
+}
+
+// addTask adds a task and its dependencies (recursively) to the graph. Tasks already in the graph are reused
+func (b *taskGraphBuilder) addTask(task *project.Task, name, localId string) (*taskNode, error) {
+	key := getTaskKey(task, localId)
+
+	if index := slices.Index(b.visiting, key); index != -1 {
+		cycle := append(slices.Clone(b.path[index:]), name)
+		return nil, fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> "))
+	}
+
+	if node, ok := b.nodes[key]; ok {
+		return node, nil
+	}
+
+	b.visiting = append(b.visiting, key)
+	b.path = append(b.path, name)
+	defer func() {
+		b.visiting = b.visiting[:len(b.visiting)-1]
+		b.path = b.path[:len(b.path)-1]
+	}()
+
+	node := taskNode{
+		name:    name,
+		localId: localId,
+		task:    task,
+		done:    make(chan struct{}),
+	}
+
+	// The dependencies are relative to the project of the task, but their names are shown relative to the current project
+	namePrefix, _ := cutProjectPrefix(name)
+	if namePrefix != "" {
+		namePrefix += ":"
+	}
+
+	for _, depId := range task.Deps {
+		depName, _, _ := strings.Cut(depId, ".")
+		depTask, err := task.Project.GetTaskByName(depName)
+		if err != nil {
+			return nil, fmt.Errorf("error getting dependency '%s' of task '%s': %w", depId, name, err)
+		}
+
+		dep, err := b.addTask(depTask, namePrefix+depId, getTaskLocalId(depId))
+		if err != nil {
+			return nil, err
+		}
+
+		node.deps = append(node.deps, dep)
+	}
+
+	b.nodes[key] = &node
+	return &node, nil
+}
+
+// cutProjectPrefix splits a task name in the project prefix and the name relative to its project. Example: 'lib:sub:build' -> 'lib:sub',
+// 'build'
+func cutProjectPrefix(name string) (prefix string, localName string) {
+	index := strings.LastIndex(name, ":")
+	if index == -1 {
+		return "", name
+	}
+
+	return name[:index], name[index+1:]
+}
+
+// getDeps returns all the dependencies of a task (direct and indirect), without repetition
+func (n *taskNode) getDeps() []*taskNode {
+	deps := []*taskNode{}
+
+	var visit func(node *taskNode)
+	visit = func(node *taskNode) {
+		for _, dep := range node.deps {
+			if !slices.Contains(deps, dep) {
+				visit(dep)
+				deps = append(deps, dep)
+			}
+		}
+	}
+	visit(n)
+
+	return deps
+}
+
+// runTaskDeps runs the dependencies of a task. The dependencies run in parallel, except if a dependency depends on another (it waits the
+// other to end). Each dependency runs once, even if required by multiple tasks. A dependency is not run if one of its dependencies fails
+func (a *App) runTaskDeps(runtime *luaruntime.Runtime, task *project.Task, taskId string) error {
+	root, err := buildTaskGraph(task, taskId)
+	if err != nil {
+		return err
+	}
+
+	deps := root.getDeps()
+
+	// Output of the dependencies
+	prefixWidth := 0
+	for _, node := range deps {
+		prefixWidth = max(prefixWidth, len(node.name))
+		node.clr = colorgen.Next()
+	}
+
+	multi := multiplexer.New(func(entry *multiplexer.Entry) error {
+		output := os.Stdout
+		if entry.Client.GetLevel() == "stderr" {
+			output = os.Stderr
+		}
+
+		prefix := entry.Client.GetColor().Sprintf("%-*s |", prefixWidth, entry.Client.GetName())
+		_, err := fmt.Fprintf(output, "%s %s\n", prefix, entry.Line)
+		return err
+	}, nil)
+
+	for _, node := range deps {
+		go func() {
+			defer close(node.done)
+			node.err = a.runTaskNode(runtime, node, multi)
+		}()
+	}
+
+	err = waitTaskDeps(root) // A task ends after its dependencies, so all dependencies ended
+	return errors.Join(err, multi.Close())
+}
+
+// waitTaskDeps waits the direct dependencies of a task to end. Returns their errors
+func waitTaskDeps(node *taskNode) error {
+	errs := []error{}
+	for _, dep := range node.deps {
+		<-dep.done
+
+		if dep.err != nil {
+			errs = append(errs, fmt.Errorf("dependency '%s' failed: %w", dep.name, dep.err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// runTaskNode runs a task of the dependency graph after its dependencies end. The task runs in a new Falcula process (in raw mode and
+// without dependencies), because the Lua runtime and the TUI can only run one task at a time. Its output is sent to the multiplexer,
+// prefixed by the task name
+func (a *App) runTaskNode(runtime *luaruntime.Runtime, node *taskNode, multi *multiplexer.Multiplexer) error {
+	err := waitTaskDeps(node)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"task", "run", "--raw", "--no-deps"}
+	if a.keepLog {
+		args = append(args, "--keep-log")
+	}
+	if a.dryRunPlan != nil {
+		args = append(args, "--dry-run")
+	}
+	if a.force {
+		args = append(args, "--force")
+	}
+	args = append(args, node.localId)
+
+	cmd, err := newFalculaCmd(node.task.Project.Folder, args...)
+	if err != nil {
+		return err
+	}
+	stdout := multi.NewClient(node.name, "stdout", node.clr)
+	stderr := multi.NewClient(node.name, "stderr", node.clr)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	runtime.Logger.LogDebug(fmt.Sprintf("Running task '%s'\n", node.name))
+	err = cmd.Run()
+	err = errors.Join(err, stdout.Flush(), stderr.Flush())
+	if err != nil {
+		return err
+	}
+	runtime.Logger.LogDebug(fmt.Sprintf("Task '%s' ended\n", node.name))
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package falcula
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/LucasAVasco/falcula/lua/luaruntime"
+)
+
+// runCmd runs the command of a script or task. In a dry run, only adds it to the plan and logs the plan
+func (a *App) runCmd(runtime *luaruntime.Runtime, name string, cmd *exec.Cmd) error {
+	if a.dryRunPlan == nil {
+		return cmd.Run()
+	}
+
+	// Only the environment variables added by falcula
+	environ := os.Environ()
+	env := []string{}
+	for _, variable := range cmd.Env {
+		if !slices.Contains(environ, variable) {
+			env = append(env, variable)
+		}
+	}
+
+	dir := cmd.Dir
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+
+	a.dryRunPlan.AddCommand(name, cmd.Args, dir, env)
+	return a.finishDryRun(runtime, nil)
+}
+
+// resetDryRun forgets the commands of the previous run (e.g. the script is re-run)
+func (a *App) resetDryRun() {
+	if a.dryRunPlan != nil {
+		a.dryRunPlan.Reset()
+	}
+}
+
+// finishDryRun logs the plan of the dry run. Returns an error if the script failed. The script error is optional
+func (a *App) finishDryRun(runtime *luaruntime.Runtime, scriptErr error) error {
+	plan := strings.Builder{}
+	err := a.dryRunPlan.Write(&plan)
+	if err != nil {
+		return fmt.Errorf("error writing dry run plan: %w", err)
+	}
+
+	runtime.Logger.LogDebug(plan.String())
+
+	if scriptErr != nil {
+		return ErrScriptFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,258 @@
+// Package dryrun implements the plan of a dry run: the processes of the services are recorded instead of spawned, and the plan shows them in
+// the order of the managers and their services, grouped by phase (prepare, start and stop)
+package dryrun
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/LucasAVasco/falcula/lua/modules/modmanager"
+	"github.com/LucasAVasco/falcula/process"
+	"github.com/LucasAVasco/falcula/service/enhanced"
+	"github.com/LucasAVasco/falcula/service/manager"
+	"github.com/LucasAVasco/falcula/service/status"
+)
+
+// Phases of the recorded commands. The commands of the scripts and tasks (not managed by a service) are in the 'run' phase
+const (
+	PhasePrepare = "prepare"
+	PhaseStart   = "start"
+	PhaseStop    = "stop"
+	PhaseRun     = "run"
+)
+
+// platformEnv is the environment variable with the platform of the docker commands
+const platformEnv = "DOCKER_DEFAULT_PLATFORM"
+
+// Command is a recorded command
+type Command struct {
+	Manager string // Empty if the command is not managed by a service
+	Service string // Service or process name
+	Phase   string
+	Args    []string // Command and its arguments
+	Dir     string
+	Env     []string // Environment variables added to the command
+	Limits  string   // Resource limits (see `process.Limits.String`). Empty if not limited
+}
+
+// Plan records the commands of a dry run. Implements `process.Recorder`. Its callbacks must be provided to the manager module (see
+// `modules.AllModulesLoaderOptions`) in order to know the manager and the phase of the processes
+type Plan struct {
+	mutex    sync.Mutex
+	managers []*manager.Manager
+	services []plannedService
+	commands []*Command
+}
+
+type plannedService struct {
+	manager *manager.Manager
+	service *enhanced.EnhancedService
+}
+
+// New creates a plan
+func New() *Plan {
+	return &Plan{}
+}
+
+// Reset forgets the recorded commands and services (e.g. the script is re-run)
+func (p *Plan) Reset() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.managers = nil
+	p.services = nil
+	p.commands = nil
+}
+
+// GetManagerCallbacks returns the callbacks that register the managers and services
+func (p *Plan) GetManagerCallbacks() *modmanager.Callbacks {
+	return &modmanager.Callbacks{
+		OnNewManager: func(man *manager.Manager) {
+			p.mutex.Lock()
+			defer p.mutex.Unlock()
+
+			p.addManagerWithoutLock(man)
+		},
+		OnAddService: func(man *manager.Manager, svc *enhanced.EnhancedService) {
+			p.mutex.Lock()
+			defer p.mutex.Unlock()
+
+			p.addManagerWithoutLock(man)
+			p.services = append(p.services, plannedService{manager: man, service: svc})
+		},
+	}
+}
+
+func (p *Plan) addManagerWithoutLock(man *manager.Manager) {
+	if !slices.Contains(p.managers, man) {
+		p.managers = append(p.managers, man)
+	}
+}
+
+// RecordProcess records a process of a service. The phase is the current step of the service
+func (p *Plan) RecordProcess(record *process.Record) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	command := Command{
+		Service: record.Name,
+		Phase:   PhaseRun,
+		Args:    record.Args,
+		Dir:     record.Dir,
+		Env:     record.Env,
+	}
+	if !record.Limits.IsEmpty() {
+		command.Limits = record.Limits.String()
+	}
+
+	// The process name is the service name
+	for _, planned := range p.services {
+		if planned.service.GetName() == record.Name {
+			command.Manager = planned.manager.GetName()
+			command.Phase = getPhase(planned.service.GetStatus())
+			break
+		}
+	}
+
+	p.commands = append(p.commands, &command)
+}
+
+// AddCommand records a command that is not managed by a service (e.g. the command of a script)
+func (p *Plan) AddCommand(name string, args []string, dir string, env []string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.commands = append(p.commands, &Command{
+		Service: name,
+		Phase:   PhaseRun,
+		Args:    args,
+		Dir:     dir,
+		Env:     env,
+	})
+}
+
+// getPhase returns the phase of a service with the provided status
+func getPhase(s status.Status) string {
+	switch s {
+	case status.Preparing:
+		return PhasePrepare
+	case status.Running:
+		return PhaseStart
+	case status.Stopping:
+		return PhaseStop
+	default:
+		return strings.ToLower(s.ToString())
+	}
+}
+
+// GetCommands returns the recorded commands in the order of the plan: commands not managed by a service first, then the managers in order of
+// creation. The commands of a manager are grouped by phase and sorted by the order of their services
+func (p *Plan) GetCommands() []*Command {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	managerIndex := func(name string) int {
+		if name == "" {
+			return -1
+		}
+		return slices.IndexFunc(p.managers, func(man *manager.Manager) bool { return man.GetName() == name })
+	}
+
+	serviceIndex := func(command *Command) int {
+		return slices.IndexFunc(p.services, func(planned plannedService) bool {
+			return planned.manager.GetName() == command.Manager && planned.service.GetName() == command.Service
+		})
+	}
+
+	phaseIndex := func(phase string) int {
+		index := slices.Index([]string{PhaseRun, PhasePrepare, PhaseStart, PhaseStop}, phase)
+		if index < 0 {
+			return 4
+		}
+		return index
+	}
+
+	commands := slices.Clone(p.commands)
+	slices.SortStableFunc(commands, func(a, b *Command) int {
+		return cmp.Or(
+			cmp.Compare(managerIndex(a.Manager), managerIndex(b.Manager)),
+			cmp.Compare(phaseIndex(a.Phase), phaseIndex(b.Phase)),
+			cmp.Compare(serviceIndex(a), serviceIndex(b)),
+		)
+	})
+
+	return commands
+}
+
+// Write writes the plan in a human-readable format
+func (p *Plan) Write(w io.Writer) error {
+	commands := p.GetCommands()
+	if len(commands) == 0 {
+		_, err := fmt.Fprintln(w, "Dry run: no command would be executed")
+		return err
+	}
+
+	builder := strings.Builder{}
+	builder.WriteString("Dry run: the following commands would be executed\n")
+
+	lastGroup, lastPhase := "", ""
+	for i, command := range commands {
+		group := "commands"
+		if command.Manager != "" {
+			group = fmt.Sprintf("manager '%s'", command.Manager)
+		}
+
+		if i == 0 || group != lastGroup {
+			fmt.Fprintf(&builder, "\n%s\n", group)
+			lastPhase = ""
+		}
+		if command.Phase != lastPhase {
+			fmt.Fprintf(&builder, "  %s:\n", command.Phase)
+		}
+		lastGroup, lastPhase = group, command.Phase
+
+		fmt.Fprintf(&builder, "    %s: %s\n", command.Service, QuoteArgs(command.Args))
+		fmt.Fprintf(&builder, "      dir: %s\n", command.Dir)
+		if command.Limits != "" {
+			fmt.Fprintf(&builder, "      limits: %s\n", command.Limits)
+		}
+
+		for _, env := range command.Env {
+			name, value, _ := strings.Cut(env, "=")
+			if name == platformEnv {
+				fmt.Fprintf(&builder, "      platform: %s\n", value)
+			} else {
+				fmt.Fprintf(&builder, "      env: %s=%s\n", name, quoteArg(value))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// safeArgRegex matches the arguments that do not need to be quoted in a shell
+var safeArgRegex = regexp.MustCompile(`^[\w@%+=:,./-]+$`)
+
+// QuoteArgs joins the arguments of a command, quoting them as a POSIX shell would need
+func QuoteArgs(args []string) string {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		quoted = append(quoted, quoteArg(arg))
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+func quoteArg(arg string) string {
+	if safeArgRegex.MatchString(arg) {
+		return arg
+	}
+
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
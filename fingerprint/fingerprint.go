@@ -0,0 +1,172 @@
+// Package fingerprint computes the fingerprints of the input and output files of the tasks, so the tasks are skipped when nothing changed
+package fingerprint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// CacheDirName is the name of the directory, inside the project folder, where Falcula stores its cache
+const CacheDirName = ".falcula"
+
+// Fingerprint is the fingerprint of a task. Each hash is a SHA-256 of the paths and contents of the matched files
+type Fingerprint struct {
+	Sources   string `json:"sources"`   // Hash of the input files and the task definition
+	Generates string `json:"generates"` // Hash of the output files
+
+	MissingGenerates []string `json:"-"` // Output patterns that do not match any file. The task is stale while they are missing
+}
+
+// Status is the result of comparing the current fingerprint of a task to the fingerprint of its last successful run
+type Status string
+
+const (
+	StatusUpToDate         Status = "up to date"
+	StatusNeverRun         Status = "never run"
+	StatusSourcesChanged   Status = "sources changed"
+	StatusGeneratesChanged Status = "generated files changed"
+	StatusGeneratesMissing Status = "generated files missing"
+)
+
+// Compute computes the fingerprint of the files that match the source and output patterns (see `Glob`). The definition is included in
+// the sources hash, so a change in the definition (e.g. the task command) also changes the fingerprint
+func Compute(dir string, sources []string, generates []string, definition string) (*Fingerprint, error) {
+	sourcesHash, err := hashFiles(dir, sources, definition)
+	if err != nil {
+		return nil, fmt.Errorf("error hashing sources: %w", err)
+	}
+
+	generatesHash, err := hashFiles(dir, generates, "")
+	if err != nil {
+		return nil, fmt.Errorf("error hashing generated files: %w", err)
+	}
+
+	missingGenerates, err := findMissing(dir, generates)
+	if err != nil {
+		return nil, fmt.Errorf("error checking generated files: %w", err)
+	}
+
+	return &Fingerprint{
+		Sources:          sourcesHash,
+		Generates:        generatesHash,
+		MissingGenerates: missingGenerates,
+	}, nil
+}
+
+// findMissing returns the patterns that do not match any file (see `Glob`)
+func findMissing(dir string, patterns []string) ([]string, error) {
+	missing := []string{}
+
+	for _, pattern := range patterns {
+		files, err := Glob(dir, []string{pattern})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(files) == 0 {
+			missing = append(missing, pattern)
+		}
+	}
+
+	return missing, nil
+}
+
+// hashFiles hashes the paths and contents of the files that match the patterns. The prefix is hashed before the files
+func hashFiles(dir string, patterns []string, prefix string) (string, error) {
+	files, err := Glob(dir, patterns)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	io.WriteString(hash, prefix+"\x00")
+
+	for _, file := range files {
+		fileHash, err := hashFile(filepath.Join(dir, filepath.FromSlash(file)))
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(hash, "%s\x00%s\n", file, fileHash)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// hashFile returns the SHA-256 of the content of a file
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", fmt.Errorf("error reading file '%s': %w", path, err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Read reads a fingerprint file. Returns nil if the file does not exist
+func Read(path string) (*Fingerprint, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading fingerprint file: %w", err)
+	}
+
+	fingerprint := Fingerprint{}
+	err = json.Unmarshal(data, &fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing fingerprint file '%s': %w", path, err)
+	}
+
+	return &fingerprint, nil
+}
+
+// Write writes the fingerprint to a file. Creates the parent directories if they do not exist
+func (f *Fingerprint) Write(path string) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return fmt.Errorf("error creating fingerprint directory: %w", err)
+	}
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("error encoding fingerprint: %w", err)
+	}
+
+	err = os.WriteFile(path, data, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing fingerprint file: %w", err)
+	}
+
+	return nil
+}
+
+// Compare compares the current fingerprint to the fingerprint of the last run (nil if the task never ran). The task is stale while any
+// of its output patterns does not match a file, even if they did not match at the last run
+func (f *Fingerprint) Compare(last *Fingerprint) Status {
+	switch {
+	case last == nil:
+		return StatusNeverRun
+	case f.Sources != last.Sources:
+		return StatusSourcesChanged
+	case len(f.MissingGenerates) > 0:
+		return StatusGeneratesMissing
+	case f.Generates != last.Generates:
+		return StatusGeneratesChanged
+	default:
+		return StatusUpToDate
+	}
+}
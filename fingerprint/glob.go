@@ -0,0 +1,112 @@
+package fingerprint
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// skippedDirs are the directories that are never matched by the globs (version control and the Falcula cache)
+var skippedDirs = []string{".git", CacheDirName}
+
+// Glob returns the files inside a directory that match any of the patterns. The patterns are relative to the directory, use the
+// `path.Match` syntax and '**' to match any number of directories (e.g. 'src/**/*.go'). A pattern that matches a directory matches all its
+// files. The returned files are relative to the directory, use '/' as separator and are sorted
+func Glob(dir string, patterns []string) ([]string, error) {
+	files := []string{}
+
+	for _, pattern := range patterns {
+		segments := strings.Split(path.Clean(filepath.ToSlash(pattern)), "/")
+		for _, segment := range segments {
+			_, err := path.Match(segment, "")
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern '%s': %w", pattern, err)
+			}
+		}
+
+		// Only walks the path before the first segment with a wildcard (a file if the pattern has no wildcard)
+		prefix := []string{}
+		for _, segment := range segments {
+			if segment == "**" || strings.ContainsAny(segment, `*?[\`) {
+				break
+			}
+			prefix = append(prefix, segment)
+		}
+
+		root := filepath.Join(dir, filepath.FromSlash(strings.Join(prefix, "/")))
+		err := filepath.WalkDir(root, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if entry.IsDir() {
+				if slices.Contains(skippedDirs, entry.Name()) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			relative, err := filepath.Rel(dir, file)
+			if err != nil {
+				return err
+			}
+			relative = filepath.ToSlash(relative)
+
+			if matchFile(segments, strings.Split(relative, "/")) && !slices.Contains(files, relative) {
+				files = append(files, relative)
+			}
+
+			return nil
+		})
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("error searching files of pattern '%s': %w", pattern, err)
+		}
+	}
+
+	slices.Sort(files)
+	return files, nil
+}
+
+// matchFile checks if the pattern matches the file or one of its parent directories
+func matchFile(pattern []string, file []string) bool {
+	for i := 1; i <= len(file); i++ {
+		if matchSegments(pattern, file[:i]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchSegments checks if the pattern matches the path. Both are split by '/'. The '**' segment matches zero or more segments
+func matchSegments(pattern []string, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+
+		matched, _ := path.Match(pattern[0], name[0])
+		if !matched {
+			return false
+		}
+
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
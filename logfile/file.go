@@ -1,4 +1,4 @@
-// Package logfile generates a log file in the temporary directory of the current user
+// Package logfile generates the session log files and manages the sessions kept in a log directory
 package logfile
 
 import (
@@ -9,18 +9,32 @@ import (
 	"time"
 )
 
-// New creates a new log file in the temporary directory of the current user. You need to close and delete the log file manually.
-func New() (*os.File, error) {
+// Extension is the extension of the session log files
+const Extension = ".log"
+
+// GetDefaultDir returns the default directory of the session log files: a directory in the temporary directory of the current user
+func GetDefaultDir() (string, error) {
 	// Get current user
 	currentUser, err := user.Current()
 	if err != nil {
-		return nil, fmt.Errorf("error getting current user: %w", err)
+		return "", fmt.Errorf("error getting current user: %w", err)
 	}
 
-	// Temporary directory
-	tmpDir := os.TempDir() + "/falcula-" + currentUser.Username
+	return filepath.Join(os.TempDir(), "falcula-"+currentUser.Username), nil
+}
+
+// New creates a new session log file in the provided directory. Uses the default directory (see `GetDefaultDir`) if the directory is empty.
+// You need to close and delete the log file manually.
+func New(dir string) (*os.File, error) {
+	if dir == "" {
+		var err error
+		dir, err = GetDefaultDir()
+		if err != nil {
+			return nil, fmt.Errorf("error getting default log directory: %w", err)
+		}
+	}
 
-	err = os.MkdirAll(tmpDir, 0700)
+	err := os.MkdirAll(dir, 0700)
 	if err != nil {
 		return nil, fmt.Errorf("error creating log file directory: %w", err)
 	}
@@ -33,9 +47,9 @@ func New() (*os.File, error) {
 
 	currentDir = filepath.Base(currentDir)
 
-	// Creates the log file
-	logFilePath := currentDir + "-" + time.Now().Format(time.RFC3339)
-	logFile, err := os.CreateTemp(tmpDir, logFilePath)
+	// Creates the log file. The session name is the file name without the extension
+	logFilePattern := currentDir + "-" + time.Now().Format("20060102-150405") + "-*" + Extension
+	logFile, err := os.CreateTemp(dir, logFilePattern)
 	if err != nil {
 		return nil, fmt.Errorf("error opening log file: %w", err)
 	}
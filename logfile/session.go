@@ -0,0 +1,262 @@
+package logfile
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// CompressedExtension is the extension of the compressed session log files
+const CompressedExtension = Extension + ".gz"
+
+// Session is a session log file kept in a log directory
+type Session struct {
+	Name       string // File name without the extension
+	Path       string
+	Size       int64
+	ModTime    time.Time
+	Compressed bool
+}
+
+// getSessionName returns the session name of a log file and whether it is compressed. Returns an empty name if the file is not a session
+// log file
+func getSessionName(fileName string) (name string, compressed bool) {
+	if name, ok := strings.CutSuffix(fileName, CompressedExtension); ok {
+		return name, true
+	}
+
+	if name, ok := strings.CutSuffix(fileName, Extension); ok {
+		return name, false
+	}
+
+	return "", false
+}
+
+// ListSessions returns the sessions of a log directory sorted from the oldest to the newest. Returns an empty list if the directory does
+// not exist
+func ListSessions(dir string) ([]*Session, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []*Session{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading log directory '%s': %w", dir, err)
+	}
+
+	sessions := make([]*Session, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() {
+			continue
+		}
+
+		name, compressed := getSessionName(dirEntry.Name())
+		if name == "" {
+			continue
+		}
+
+		info, err := dirEntry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("error getting information of log file '%s': %w", dirEntry.Name(), err)
+		}
+
+		sessions = append(sessions, &Session{
+			Name:       name,
+			Path:       filepath.Join(dir, dirEntry.Name()),
+			Size:       info.Size(),
+			ModTime:    info.ModTime(),
+			Compressed: compressed,
+		})
+	}
+
+	slices.SortFunc(sessions, func(a, b *Session) int {
+		return a.ModTime.Compare(b.ModTime)
+	})
+
+	return sessions, nil
+}
+
+// FindSession returns the session of a log directory with the provided name. The name can be a unique prefix of the session name. If the
+// name is empty, returns the newest session
+func FindSession(dir string, name string) (*Session, error) {
+	sessions, err := ListSessions(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sessions) == 0 {
+		return nil, fmt.Errorf("there are no sessions in the log directory '%s'", dir)
+	}
+
+	if name == "" {
+		return sessions[len(sessions)-1], nil
+	}
+
+	var found *Session
+	for _, session := range sessions {
+		if session.Name == name {
+			return session, nil
+		}
+
+		if strings.HasPrefix(session.Name, name) {
+			if found != nil {
+				return nil, fmt.Errorf("the session name '%s' is ambiguous", name)
+			}
+			found = session
+		}
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("session '%s' not found in the log directory '%s'", name, dir)
+	}
+
+	return found, nil
+}
+
+// Open opens the session log file for reading. Decompresses it if it is compressed
+func (s *Session) Open() (io.ReadCloser, error) {
+	file, err := os.Open(s.Path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening log file: %w", err)
+	}
+
+	if !s.Compressed {
+		return file, nil
+	}
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error decompressing log file: %w", err)
+	}
+
+	return &gzipFile{Reader: reader, file: file}, nil
+}
+
+// gzipFile is a gzip reader that also closes the underlying file
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (g *gzipFile) Close() error {
+	return errors.Join(g.Reader.Close(), g.file.Close())
+}
+
+// compress compresses the session log file with gzip and removes the uncompressed file
+func (s *Session) compress() error {
+	if s.Compressed {
+		return nil
+	}
+
+	src, err := os.Open(s.Path)
+	if err != nil {
+		return fmt.Errorf("error opening log file: %w", err)
+	}
+	defer src.Close()
+
+	destPath := strings.TrimSuffix(s.Path, Extension) + CompressedExtension
+	dest, err := os.OpenFile(destPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("error creating compressed log file: %w", err)
+	}
+
+	writer := gzip.NewWriter(dest)
+	_, err = io.Copy(writer, src)
+	if err == nil {
+		err = writer.Close()
+	}
+	if err == nil {
+		err = dest.Close()
+	} else {
+		dest.Close()
+	}
+	if err != nil {
+		os.Remove(destPath)
+		return fmt.Errorf("error compressing log file: %w", err)
+	}
+
+	// Keeps the modification time, so the sessions order is preserved
+	os.Chtimes(destPath, s.ModTime, s.ModTime)
+
+	err = os.Remove(s.Path)
+	if err != nil {
+		return fmt.Errorf("error removing uncompressed log file: %w", err)
+	}
+
+	info, err := os.Stat(destPath)
+	if err == nil {
+		s.Size = info.Size()
+	}
+	s.Path = destPath
+	s.Compressed = true
+
+	return nil
+}
+
+// RetentionPolicy is the policy used to decide which sessions are kept in a log directory
+type RetentionPolicy struct {
+	KeepSessions int   // Maximum number of sessions. Unlimited if not positive
+	MaxSize      int64 // Maximum size (in bytes) of all sessions. Unlimited if not positive
+	Compress     bool  // Compress the old sessions with gzip
+}
+
+// Rotate applies the retention policy to a log directory. The session of the provided log file (the current session) is never compressed
+// or removed. The same applies to the sessions reported as active by `isActive` (optional), as the log directory can be shared by other
+// running sessions. The oldest sessions are removed first
+func Rotate(dir string, policy *RetentionPolicy, currentPath string, isActive func(session *Session) bool) error {
+	sessions, err := ListSessions(dir)
+	if err != nil {
+		return err
+	}
+
+	// The current and active sessions are not affected by the policy, but count to the limits
+	numSessions := len(sessions)
+	totalSize := int64(0)
+	oldSessions := make([]*Session, 0, len(sessions))
+	for _, session := range sessions {
+		totalSize += session.Size
+		if session.Path != currentPath && (isActive == nil || !isActive(session)) {
+			oldSessions = append(oldSessions, session)
+		}
+	}
+
+	errs := []error{}
+
+	// Compression
+	if policy.Compress {
+		for _, session := range oldSessions {
+			oldSize := session.Size
+			err := session.compress()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("error compressing session '%s': %w", session.Name, err))
+				continue
+			}
+			totalSize += session.Size - oldSize
+		}
+	}
+
+	// Removes the oldest sessions until the limits are satisfied
+	for _, session := range oldSessions {
+		tooMany := policy.KeepSessions > 0 && numSessions > policy.KeepSessions
+		tooBig := policy.MaxSize > 0 && totalSize > policy.MaxSize
+		if !tooMany && !tooBig {
+			break
+		}
+
+		err := os.Remove(session.Path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error removing session '%s': %w", session.Name, err))
+			continue
+		}
+
+		numSessions--
+		totalSize -= session.Size
+	}
+
+	return errors.Join(errs...)
+}
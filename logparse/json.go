@@ -0,0 +1,73 @@
+package logparse
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// JSON parses logs with a JSON object per line. Nested objects and arrays are kept as compact JSON in the fields
+type JSON struct{}
+
+func (j *JSON) Parse(line string) (*Record, bool) {
+	if !strings.HasPrefix(strings.TrimSpace(line), "{") {
+		return nil, false
+	}
+
+	// Uses the tokens of the decoder instead of a map to keep the order of the fields
+	decoder := json.NewDecoder(strings.NewReader(line))
+	decoder.UseNumber()
+
+	token, err := decoder.Token()
+	if err != nil || token != json.Delim('{') {
+		return nil, false
+	}
+
+	builder := recordBuilder{}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, false
+		}
+
+		key, ok := token.(string)
+		if !ok {
+			return nil, false
+		}
+
+		var raw json.RawMessage
+		err = decoder.Decode(&raw)
+		if err != nil {
+			return nil, false
+		}
+
+		value, isNumber := jsonValueToString(raw)
+		builder.add(key, value, isNumber)
+	}
+
+	// Closing delimiter
+	_, err = decoder.Token()
+	if err != nil {
+		return nil, false
+	}
+
+	return &builder.record, true
+}
+
+// jsonValueToString converts a raw JSON value to a string. Strings are unquoted and other values are compacted. Also returns whether the
+// value is a number
+func jsonValueToString(raw json.RawMessage) (string, bool) {
+	var str string
+	if json.Unmarshal(raw, &str) == nil {
+		return str, false
+	}
+
+	buffer := bytes.Buffer{}
+	if json.Compact(&buffer, raw) != nil {
+		return string(raw), false
+	}
+
+	value := buffer.String()
+	isNumber := len(value) > 0 && (value[0] == '-' || (value[0] >= '0' && value[0] <= '9'))
+	return value, isNumber
+}
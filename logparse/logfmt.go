@@ -0,0 +1,76 @@
+package logparse
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Logfmt parses logs with the logfmt format: space separated key-value pairs with optionally quoted values (e.g. 'level=info msg="server
+// started" port=80'). Keys without value are fields with an empty value
+type Logfmt struct{}
+
+func (l *Logfmt) Parse(line string) (*Record, bool) {
+	builder := recordBuilder{}
+	numPairs := 0
+	rest := strings.TrimSpace(line)
+
+	for rest != "" {
+		// Key
+		end := strings.IndexAny(rest, "= ")
+		if end == 0 {
+			return nil, false
+		}
+
+		key := rest
+		rest = ""
+		if end > 0 {
+			key, rest = key[:end], key[end:]
+		}
+
+		if strings.ContainsRune(key, '"') {
+			return nil, false
+		}
+
+		// Value
+		value := ""
+		isNumber := false
+		if strings.HasPrefix(rest, "=") {
+			rest = rest[1:]
+			numPairs++
+
+			if strings.HasPrefix(rest, `"`) {
+				quoted, err := strconv.QuotedPrefix(rest)
+				if err != nil {
+					return nil, false
+				}
+
+				rest = rest[len(quoted):]
+				value, _ = strconv.Unquote(quoted)
+			} else {
+				end := strings.IndexByte(rest, ' ')
+				if end < 0 {
+					end = len(rest)
+				}
+
+				value, rest = rest[:end], rest[end:]
+				_, err := strconv.ParseFloat(value, 64)
+				isNumber = err == nil
+			}
+		}
+
+		// Pairs must be separated by spaces
+		if rest != "" && rest[0] != ' ' {
+			return nil, false
+		}
+		rest = strings.TrimLeft(rest, " ")
+
+		builder.add(key, value, isNumber)
+	}
+
+	// Plain text is not a logfmt log
+	if numPairs == 0 {
+		return nil, false
+	}
+
+	return &builder.record, true
+}
@@ -0,0 +1,183 @@
+// Package logparse parses structured service logs (JSON, logfmt or a custom regular expression) and extracts their level, message and
+// timestamp
+package logparse
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formats supported by `New`
+const (
+	FormatJSON        = "json"   // JSON object per line (e.g. zap, pino, slog)
+	FormatLogfmt      = "logfmt" // Key-value pairs per line (e.g. 'level=info msg="started" port=80')
+	FormatRegexPrefix = "regex:" // Regular expression with named groups (e.g. 'regex:^(?P<level>\w+) (?P<message>.*)$')
+)
+
+// Normalized levels returned by `NormalizeLevel`
+const (
+	LevelTrace = "trace"
+	LevelDebug = "debug"
+	LevelInfo  = "info"
+	LevelWarn  = "warn"
+	LevelError = "error"
+	LevelFatal = "fatal"
+)
+
+// Field is a key-value pair of a structured log that is not the level, message or timestamp
+type Field struct {
+	Key   string
+	Value string
+}
+
+// Record is a parsed log line
+type Record struct {
+	Level   string    // Normalized level (see `NormalizeLevel`). Empty if the log has no level
+	Message string    // Empty if the log has no message
+	Time    time.Time // Zero if the log has no timestamp
+	Fields  []Field   // Other fields, in the order they appear in the log
+}
+
+// Parser parses the log lines of a service
+type Parser interface {
+	// Parse parses a log line (without the trailing newline). Returns false if the line does not have the expected format
+	Parse(line string) (*Record, bool)
+}
+
+// New creates a parser from its format ('json', 'logfmt' or 'regex:<pattern>'). Returns a nil parser if the format is empty
+func New(format string) (Parser, error) {
+	if pattern, ok := strings.CutPrefix(format, FormatRegexPrefix); ok {
+		parser, err := NewRegex(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("error creating regex log parser: %w", err)
+		}
+
+		return parser, nil
+	}
+
+	switch format {
+	case "":
+		return nil, nil
+	case FormatJSON:
+		return &JSON{}, nil
+	case FormatLogfmt:
+		return &Logfmt{}, nil
+	default:
+		return nil, fmt.Errorf("invalid log format '%s', must be '%s', '%s' or '%s<pattern>'", format, FormatJSON, FormatLogfmt,
+			FormatRegexPrefix)
+	}
+}
+
+// Keys of the structured logs that hold the level, message and timestamp
+var (
+	levelKeys   = []string{"level", "lvl", "severity", "log.level", "loglevel"}
+	messageKeys = []string{"msg", "message", "log"}
+	timeKeys    = []string{"time", "ts", "timestamp", "@timestamp", "t"}
+)
+
+// recordBuilder builds a record from the key-value pairs of a structured log
+type recordBuilder struct {
+	record   Record
+	hasLevel bool
+	hasMsg   bool
+	hasTime  bool
+}
+
+// add adds a key-value pair. Numeric values are used to parse Unix timestamps
+func (b *recordBuilder) add(key string, value string, isNumber bool) {
+	lowerKey := strings.ToLower(key)
+
+	switch {
+	case !b.hasLevel && slices.Contains(levelKeys, lowerKey):
+		b.hasLevel = true
+		b.record.Level = NormalizeLevel(value)
+
+	case !b.hasMsg && slices.Contains(messageKeys, lowerKey):
+		b.hasMsg = true
+		b.record.Message = value
+
+	case !b.hasTime && slices.Contains(timeKeys, lowerKey):
+		parsed, ok := parseTime(value, isNumber)
+		if !ok {
+			b.record.Fields = append(b.record.Fields, Field{Key: key, Value: value})
+			return
+		}
+
+		b.hasTime = true
+		b.record.Time = parsed
+
+	default:
+		b.record.Fields = append(b.record.Fields, Field{Key: key, Value: value})
+	}
+}
+
+// NormalizeLevel converts a level name (e.g. 'WARNING', 'err', 'I') or a numeric level (pino and bunyan: 10 trace to 60 fatal) to one of
+// the `Level*` constants. Unknown levels are returned in lower case
+func NormalizeLevel(level string) string {
+	if number, err := strconv.Atoi(level); err == nil {
+		switch {
+		case number <= 10:
+			return LevelTrace
+		case number <= 20:
+			return LevelDebug
+		case number <= 30:
+			return LevelInfo
+		case number <= 40:
+			return LevelWarn
+		case number <= 50:
+			return LevelError
+		default:
+			return LevelFatal
+		}
+	}
+
+	level = strings.ToLower(strings.TrimSpace(level))
+	switch level {
+	case "trace", "trc", "t", "verbose":
+		return LevelTrace
+	case "debug", "dbg", "d":
+		return LevelDebug
+	case "info", "inf", "i", "information", "notice":
+		return LevelInfo
+	case "warn", "warning", "wrn", "w":
+		return LevelWarn
+	case "error", "err", "e":
+		return LevelError
+	case "fatal", "ftl", "f", "panic", "dpanic", "critical", "crit", "alert", "emergency", "emerg":
+		return LevelFatal
+	default:
+		return level
+	}
+}
+
+// parseTime parses a timestamp. Numeric timestamps are Unix times in seconds or milliseconds (detected by their magnitude)
+func parseTime(value string, isNumber bool) (time.Time, bool) {
+	if isNumber {
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+
+		// Milliseconds
+		if number > 1e11 {
+			number /= 1000
+		}
+
+		seconds, fraction := math.Modf(number)
+		return time.Unix(int64(seconds), int64(fraction*1e9)), true
+	}
+
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02 15:04:05.999999999Z07:00",
+		"2006-01-02 15:04:05.999999999"} {
+		parsed, err := time.Parse(layout, value)
+		if err == nil {
+			return parsed, true
+		}
+	}
+
+	return time.Time{}, false
+}
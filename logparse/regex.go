@@ -0,0 +1,44 @@
+package logparse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Regex parses logs with a regular expression. The named groups 'level', 'message' (or 'msg') and 'time' (or 'timestamp') are the level,
+// message and timestamp of the record. Other named groups are fields
+type Regex struct {
+	regex *regexp.Regexp
+}
+
+// NewRegex creates a parser from a regular expression with the `regexp` syntax
+func NewRegex(pattern string) (*Regex, error) {
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression '%s': %w", pattern, err)
+	}
+
+	return &Regex{
+		regex: regex,
+	}, nil
+}
+
+func (r *Regex) Parse(line string) (*Record, bool) {
+	match := r.regex.FindStringSubmatch(line)
+	if match == nil {
+		return nil, false
+	}
+
+	builder := recordBuilder{}
+	for index, name := range r.regex.SubexpNames() {
+		if name == "" || index == 0 {
+			continue
+		}
+
+		_, err := strconv.ParseFloat(match[index], 64)
+		builder.add(name, match[index], err == nil)
+	}
+
+	return &builder.record, true
+}
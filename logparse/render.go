@@ -0,0 +1,73 @@
+package logparse
+
+import (
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// levelColors are the colors of the normalized levels
+var levelColors = map[string]*color.Color{
+	LevelTrace: color.New(color.Faint),
+	LevelDebug: color.New(color.FgCyan),
+	LevelInfo:  color.New(color.FgGreen),
+	LevelWarn:  color.New(color.FgYellow),
+	LevelError: color.New(color.FgRed),
+	LevelFatal: color.New(color.FgRed, color.Bold),
+}
+
+// fieldKeyColor is the color of the keys of the fields
+var fieldKeyColor = color.New(color.Faint)
+
+// GetLevelColor returns the color of a normalized level. Returns nil if the level has no color
+func GetLevelColor(level string) *color.Color {
+	return levelColors[level]
+}
+
+// Compact renders the message and the fields of a record in a single line (e.g. 'server started port=80'). The level and timestamp are
+// not included
+func (r *Record) Compact() string {
+	builder := strings.Builder{}
+	builder.WriteString(r.Message)
+
+	for _, field := range r.Fields {
+		if builder.Len() > 0 {
+			builder.WriteByte(' ')
+		}
+
+		builder.WriteString(field.Key + "=" + quoteIfNeeded(field.Value))
+	}
+
+	return builder.String()
+}
+
+// Colorize renders the message and the fields of a record in a single line like `Compact`. The message is colored by the level and the
+// field keys are faint
+func (r *Record) Colorize() string {
+	builder := strings.Builder{}
+
+	if levelColor := GetLevelColor(r.Level); levelColor != nil && r.Message != "" {
+		builder.WriteString(levelColor.Sprint(r.Message))
+	} else {
+		builder.WriteString(r.Message)
+	}
+
+	for _, field := range r.Fields {
+		if builder.Len() > 0 {
+			builder.WriteByte(' ')
+		}
+
+		builder.WriteString(fieldKeyColor.Sprint(field.Key+"=") + quoteIfNeeded(field.Value))
+	}
+
+	return builder.String()
+}
+
+// quoteIfNeeded quotes a field value if it is empty or has spaces, so the compact representation is unambiguous
+func quoteIfNeeded(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t") {
+		return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+	}
+
+	return value
+}
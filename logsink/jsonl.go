@@ -0,0 +1,51 @@
+package logsink
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// jsonEntry is the JSON representation of an entry
+type jsonEntry struct {
+	Time    string `json:"time"`
+	Service string `json:"service"`
+	Stream  string `json:"stream"`
+	Level   string `json:"level,omitempty"`
+	Client  uint   `json:"client"`
+	Message string `json:"message"`
+}
+
+// JSONLinesFile is a sink that writes each log as a JSON object in a line of a file. Useful to keep machine-readable logs
+type JSONLinesFile struct {
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// NewJSONLinesFile creates a sink that appends the logs to the file at the provided path
+func NewJSONLinesFile(path string) (*JSONLinesFile, error) {
+	file, err := openFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JSONLinesFile{
+		file:    file,
+		encoder: json.NewEncoder(file),
+	}, nil
+}
+
+func (j *JSONLinesFile) Write(entry *Entry) error {
+	return j.encoder.Encode(&jsonEntry{
+		Time:    entry.Time.Format(time.RFC3339Nano),
+		Service: entry.Service,
+		Stream:  entry.Stream,
+		Level:   entry.Level,
+		Client:  entry.ClientId,
+		Message: entry.Message,
+	})
+}
+
+func (j *JSONLinesFile) Close() error {
+	return j.file.Close()
+}
@@ -0,0 +1,57 @@
+package logsink
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// PerService is a sink that writes the logs of each service to its own file (with the 'syslog_log' format) inside a directory
+type PerService struct {
+	dir   string
+	files map[string]*SyslogFile
+}
+
+// NewPerService creates a sink that writes the logs of each service to '<dir>/<service>.log'
+func NewPerService(dir string) (*PerService, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("the directory path is empty")
+	}
+
+	return &PerService{
+		dir:   dir,
+		files: make(map[string]*SyslogFile),
+	}, nil
+}
+
+func (p *PerService) Write(entry *Entry) error {
+	file, ok := p.files[entry.Service]
+	if !ok {
+		// The service name may have characters that are not allowed in a file name
+		fileName := strings.ReplaceAll(entry.Service, string(filepath.Separator), "_") + ".log"
+
+		var err error
+		file, err = NewSyslogFile(filepath.Join(p.dir, fileName))
+		if err != nil {
+			return fmt.Errorf("error creating log file of service '%s': %w", entry.Service, err)
+		}
+
+		p.files[entry.Service] = file
+	}
+
+	return file.Write(entry)
+}
+
+func (p *PerService) Close() error {
+	errs := []error{}
+	for service, file := range p.files {
+		err := file.Close()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error closing log file of service '%s': %w", service, err))
+		}
+	}
+
+	p.files = make(map[string]*SyslogFile)
+	return errors.Join(errs...)
+}
@@ -0,0 +1,58 @@
+package logsink
+
+import "sync"
+
+// DefaultRingSize is the default number of entries of a ring buffer sink
+const DefaultRingSize = 1000
+
+// Ring is a sink that keeps the last logs in memory. It is thread-safe
+type Ring struct {
+	mutex   sync.Mutex
+	entries []Entry
+	next    int  // Index of the next entry to write
+	full    bool // The buffer was filled at least once
+}
+
+// NewRing creates an in-memory ring buffer with the provided size. Uses `DefaultRingSize` if the size is not positive
+func NewRing(size int) *Ring {
+	if size <= 0 {
+		size = DefaultRingSize
+	}
+
+	return &Ring{
+		entries: make([]Entry, size),
+	}
+}
+
+func (r *Ring) Write(entry *Entry) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.entries[r.next] = *entry
+	r.next++
+	if r.next == len(r.entries) {
+		r.next = 0
+		r.full = true
+	}
+
+	return nil
+}
+
+// GetEntries returns a copy of the entries in the buffer, from the oldest to the newest
+func (r *Ring) GetEntries() []Entry {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if !r.full {
+		return append([]Entry{}, r.entries[:r.next]...)
+	}
+
+	entries := make([]Entry, 0, len(r.entries))
+	entries = append(entries, r.entries[r.next:]...)
+	entries = append(entries, r.entries[:r.next]...)
+	return entries
+}
+
+func (r *Ring) Close() error {
+	return nil
+}
@@ -0,0 +1,126 @@
+// Package logsink implements destinations (sinks) for the services logs
+package logsink
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Entry is a service log line sent to the sinks
+type Entry struct {
+	Time     time.Time
+	Service  string       // Name of the service that generated the log
+	Stream   string       // Stream of the log (e.g.: 'stdout', 'stderr')
+	Level    string       // Level parsed from the log (e.g.: 'info', 'error'). Empty if the service logs are not parsed or it has no level
+	ClientId uint         // ID of the multiplexer client that generated the log
+	Message  string       // Log line without the trailing newline
+	Color    *color.Color // Color of the service. Can be nil
+	UILine   string       // Entry formatted to be shown in the UI (with ANSI colors and the trailing newline). Can be empty
+}
+
+// GetLevelOrStream returns the parsed level of the entry. Returns the stream if the entry has no level
+func (e *Entry) GetLevelOrStream() string {
+	if e.Level != "" {
+		return e.Level
+	}
+
+	return e.Stream
+}
+
+// Sink is a destination of the services logs
+type Sink interface {
+	Write(entry *Entry) error
+	Close() error
+}
+
+// Sink types supported by `New`
+const (
+	TypeSyslog     = "syslog"      // File with the 'syslog_log' format recognized by 'Lnav'
+	TypeJSONLines  = "jsonl"       // File with a JSON object per line
+	TypeStdout     = "stdout"      // Standard output with the service name and stream as prefix
+	TypePerService = "per_service" // Directory with one 'syslog_log' file per service
+	TypeRing       = "ring"        // In-memory ring buffer with the last logs
+)
+
+// Config is the configuration of a sink. Used to create sinks from the project configuration file and from Lua
+type Config struct {
+	Type   string `yaml:"type" lua:"type"`     // Type of the sink. Must be one of the `Type*` constants
+	Name   string `yaml:"name" lua:"name"`     // Name of the sink. Optional, used to query the sink (e.g. entries of a ring buffer)
+	Path   string `yaml:"path" lua:"path"`     // File path (directory path for 'per_service' sinks). Required by file sinks
+	Filter string `yaml:"filter" lua:"filter"` // Glob matched against the service name (e.g. 'api.*'). Optional, all services if empty
+	Size   int    `yaml:"size" lua:"size"`     // Maximum number of entries of a 'ring' sink. Uses `DefaultRingSize` if not provided
+}
+
+// New creates a sink from its configuration. The sink is wrapped by a filter if the configuration has one
+func New(config *Config) (Sink, error) {
+	var sink Sink
+	var err error
+
+	switch config.Type {
+	case TypeSyslog:
+		sink, err = NewSyslogFile(config.Path)
+	case TypeJSONLines:
+		sink, err = NewJSONLinesFile(config.Path)
+	case TypeStdout:
+		sink = NewStdout(nil)
+	case TypePerService:
+		sink, err = NewPerService(config.Path)
+	case TypeRing:
+		sink = NewRing(config.Size)
+	default:
+		return nil, fmt.Errorf("invalid sink type '%s'", config.Type)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error creating sink of type '%s': %w", config.Type, err)
+	}
+
+	if config.Filter != "" {
+		sink, err = NewFilter(config.Filter, sink)
+		if err != nil {
+			return nil, fmt.Errorf("error creating filter of sink of type '%s': %w", config.Type, err)
+		}
+	}
+
+	return sink, nil
+}
+
+// Filter is a sink that only writes the entries of the services that match a glob to another sink
+type Filter struct {
+	pattern string
+	sink    Sink
+}
+
+// NewFilter wraps a sink with a filter. The pattern uses the `path.Match` syntax and is matched against the service name
+func NewFilter(pattern string, sink Sink) (*Filter, error) {
+	_, err := path.Match(pattern, "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter '%s': %w", pattern, err)
+	}
+
+	return &Filter{
+		pattern: pattern,
+		sink:    sink,
+	}, nil
+}
+
+// GetSink returns the wrapped sink
+func (f *Filter) GetSink() Sink {
+	return f.sink
+}
+
+func (f *Filter) Write(entry *Entry) error {
+	matched, _ := path.Match(f.pattern, entry.Service)
+	if !matched {
+		return nil
+	}
+
+	return f.sink.Write(entry)
+}
+
+func (f *Filter) Close() error {
+	return f.sink.Close()
+}
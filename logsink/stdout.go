@@ -0,0 +1,37 @@
+package logsink
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Stdout is a sink that writes the logs to the standard output (or another writer) prefixed with the service name and stream (or parsed level)
+type Stdout struct {
+	writer io.Writer
+}
+
+// NewStdout creates a sink that writes to the provided writer. Uses the standard output if the writer is nil
+func NewStdout(writer io.Writer) *Stdout {
+	if writer == nil {
+		writer = os.Stdout
+	}
+
+	return &Stdout{
+		writer: writer,
+	}
+}
+
+func (s *Stdout) Write(entry *Entry) error {
+	prefix := entry.Service + " " + entry.GetLevelOrStream() + ": "
+	if entry.Color != nil {
+		prefix = entry.Color.Sprint(prefix)
+	}
+
+	_, err := fmt.Fprint(s.writer, prefix, entry.Message, "\n")
+	return err
+}
+
+func (s *Stdout) Close() error {
+	return nil
+}
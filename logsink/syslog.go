@@ -0,0 +1,114 @@
+package logsink
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FormatSyslog formats an entry with the 'syslog_log' format recognized by 'Lnav'. The parsed level is used instead of the stream if the
+// entry has one. The returned line ends with a newline
+func FormatSyslog(entry *Entry) string {
+	return entry.Time.Format(time.RFC3339) + " " + entry.Service + " " + entry.GetLevelOrStream() + "[" + strconv.Itoa(int(entry.ClientId)) + "]: " +
+		entry.Message + "\n"
+}
+
+// ParseSyslog parses a line with the 'syslog_log' format generated by `FormatSyslog`. The line must not end with a newline. The
+// stream of the returned entry is the parsed level if the line has one
+func ParseSyslog(line string) (*Entry, error) {
+	timeString, rest, ok := strings.Cut(line, " ")
+	if !ok {
+		return nil, fmt.Errorf("missing time in log line '%s'", line)
+	}
+
+	entryTime, err := time.Parse(time.RFC3339, timeString)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time in log line '%s': %w", line, err)
+	}
+
+	service, rest, ok := strings.Cut(rest, " ")
+	if !ok {
+		return nil, fmt.Errorf("missing service in log line '%s'", line)
+	}
+
+	header, message, ok := strings.Cut(rest, "]: ")
+	if !ok {
+		return nil, fmt.Errorf("missing stream in log line '%s'", line)
+	}
+
+	stream, idString, ok := strings.Cut(header, "[")
+	if !ok {
+		return nil, fmt.Errorf("missing client ID in log line '%s'", line)
+	}
+
+	id, err := strconv.Atoi(idString)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client ID in log line '%s': %w", line, err)
+	}
+
+	return &Entry{
+		Time:     entryTime,
+		Service:  service,
+		Stream:   stream,
+		ClientId: uint(id),
+		Message:  message,
+	}, nil
+}
+
+// openFile opens a file in append mode. Creates the file and its parent directories if they do not exist
+func openFile(path string) (*os.File, error) {
+	if path == "" {
+		return nil, fmt.Errorf("the file path is empty")
+	}
+
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return nil, fmt.Errorf("error creating directory of file '%s': %w", path, err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file '%s': %w", path, err)
+	}
+
+	return file, nil
+}
+
+// SyslogFile is a sink that writes the logs to a file with the 'syslog_log' format recognized by 'Lnav'
+type SyslogFile struct {
+	file *os.File
+}
+
+// NewSyslogFile creates a sink that appends the logs to the file at the provided path
+func NewSyslogFile(path string) (*SyslogFile, error) {
+	file, err := openFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSyslogFromFile(file), nil
+}
+
+// NewSyslogFromFile creates a sink that writes the logs to an already opened file. Closing the sink closes the file
+func NewSyslogFromFile(file *os.File) *SyslogFile {
+	return &SyslogFile{
+		file: file,
+	}
+}
+
+// GetPath returns the path of the log file
+func (s *SyslogFile) GetPath() string {
+	return s.file.Name()
+}
+
+func (s *SyslogFile) Write(entry *Entry) error {
+	_, err := s.file.WriteString(FormatSyslog(entry))
+	return err
+}
+
+func (s *SyslogFile) Close() error {
+	return s.file.Close()
+}
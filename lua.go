@@ -1,44 +1,103 @@
 package falcula
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/LucasAVasco/falcula/control"
+	"github.com/LucasAVasco/falcula/logfile"
 	"github.com/LucasAVasco/falcula/lua/luaruntime"
+	"github.com/LucasAVasco/falcula/lua/luaruntime/logger"
 	"github.com/LucasAVasco/falcula/lua/modules"
-	"github.com/LucasAVasco/falcula/lua/modules/modtui"
+	"github.com/LucasAVasco/falcula/project"
 )
 
 // runLuaConfig is the configuration required to run a Lua code or file
 type runLuaConfig struct {
 	Runtime *luaruntime.Runtime
 	Code    string
-	File    string   // Path to the Lua file
-	Args    []string // Arguments provided to the code or file
+	File    string              // Path to the Lua file
+	Args    []string            // Arguments provided to the code or file
+	Params  project.ParamValues // Parameters provided to the code or file (`falcula.cmd.params`)
+	Name    string              // Script or task that runs the code or file (e.g. 'script dev'). Used in the report of the run
 
 	// Called after the code or file is executed and before waiting for the user to close the TUI. Optional (can be nil)
 	AfterRun func(runtime *luaruntime.Runtime) error
 }
 
+// newRuntime creates a new Lua runtime configured with the project settings (e.g. log sinks)
+func (a *App) newRuntime() (*luaruntime.Runtime, error) {
+	retention, err := a.project.Log.GetRetentionPolicy()
+	if err != nil {
+		return nil, fmt.Errorf("error getting log retention policy: %w", err)
+	}
+
+	runtimeOpts := luaruntime.Options{
+		Logger: &logger.Options{
+			Dir:       a.project.Log.Dir,
+			Keep:      a.keepLog || a.project.Log.Keep,
+			Retention: retention,
+			Redact:    &a.project.Log.Redact,
+			IsSessionActive: func(session *logfile.Session) bool {
+				return control.IsRunning(session.Name)
+			},
+		},
+	}
+
+	if a.dryRunPlan != nil {
+		runtimeOpts.ProcessRecorder = a.dryRunPlan
+	}
+
+	runtime, err := luaruntime.New(&runtimeOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error creating runtime: %w", err)
+	}
+
+	for _, sinkConfig := range a.project.Log.Sinks {
+		_, err := runtime.Logger.AddSinkFromConfig(sinkConfig)
+		if err != nil {
+			runtime.Close()
+			return nil, fmt.Errorf("error adding log sink of type '%s' from project configuration: %w", sinkConfig.Type, err)
+		}
+	}
+
+	return runtime, nil
+}
+
+// getUsageSampleInterval returns the interval between the resource usage samples of the services. Zero if disabled. A dry run has no
+// processes to sample
+func (a *App) getUsageSampleInterval() time.Duration {
+	if a.dryRunPlan != nil {
+		return 0
+	}
+
+	interval, _ := a.project.Resources.GetSampleInterval() // Validated when reading the project file
+	return interval
+}
+
 // runLuaCode runs a Lua code. Waits for the user to close the TUI if it is visible
 func (a *App) runLuaCode(config *runLuaConfig) error {
 	// Runs the main script. Repeats the script if the user selects new arguments
 
 	// Loding modules
+	config.Runtime.SetScriptParams(config.Params)
 	loader, err := modules.LoadAllModules(config.Runtime, &modules.AllModulesLoaderOptions{
-		RawMode:      a.rawMode,
-		OnSelectArgs: func(newArgs []string) {},
+		RawMode:             a.rawMode,
+		OnSelectArgs:        func(newArgs []string) {},
+		ManagerCallbacks:    a.getManagerCallbacks(),
+		UsageSampleInterval: a.getUsageSampleInterval(),
 	})
 	if err != nil {
 		config.Runtime.Logger.LogError(fmt.Errorf("error loading modules: %w", err))
-		return nil
+		return ErrScriptFailed
 	}
 	defer loader.Close()
 
 	// Running the script
-	err = config.Runtime.Run(config.Code, config.Args...)
-	if err != nil {
-		config.Runtime.Logger.LogError(fmt.Errorf("error running script Lua code: %w", err))
+	scriptErr := config.Runtime.Run(config.Code, config.Args...)
+	if scriptErr != nil {
+		config.Runtime.Logger.LogError(fmt.Errorf("error running script Lua code: %w", scriptErr))
 	}
 
 	// Calling the after run callback
@@ -51,15 +110,19 @@ func (a *App) runLuaCode(config *runLuaConfig) error {
 
 	// Wait until the TUI is closed or the user selects new arguments
 	for {
-		if modtui.TuiIsVisible() {
-			time.Sleep(100 * time.Millisecond)
+		if a.keepSessionRunning() {
+			config.Runtime.SleepWithCallbacks(100 * time.Millisecond) // The Lua functions of the output triggers still run
 			continue
 		}
 
 		break
 	}
 
-	return nil
+	if a.dryRunPlan != nil {
+		return a.finishDryRun(config.Runtime, scriptErr)
+	}
+
+	return a.finishReport(config.Runtime, config.Name, scriptErr)
 }
 
 // runLuaFile runs a Lua file. Waits for the user to close the TUI if it is visible
@@ -71,33 +134,54 @@ func (a *App) runLuaFile(config *runLuaConfig) error {
 
 		// Loding modules
 		var err error
+		onSelectArgs := func(newArgs []string) {
+			reRunScript = true
+			config.File = config.Runtime.GetLastExecutedFile()
+			config.Args = newArgs
+
+			err := loader.Close()
+			if err != nil {
+				config.Runtime.Logger.LogError(fmt.Errorf("error closing Lua modules loader: %w", err))
+				config.Runtime.CloseLuaState()
+				return
+			}
+
+			config.Runtime.ResetLuaState()
+		}
+
+		config.Runtime.SetScriptParams(config.Params)
 		loader, err = modules.LoadAllModules(config.Runtime, &modules.AllModulesLoaderOptions{
-			RawMode: a.rawMode,
-			OnSelectArgs: func(newArgs []string) {
-				reRunScript = true
-				config.File = config.Runtime.GetLastExecutedFile()
-				config.Args = newArgs
-
-				err := loader.Close()
-				if err != nil {
-					config.Runtime.Logger.LogError(fmt.Errorf("error closing Lua modules loader: %w", err))
-					config.Runtime.CloseLuaState()
-					return
-				}
-
-				config.Runtime.ResetLuaState()
-			},
+			RawMode:             a.rawMode,
+			OnSelectArgs:        onSelectArgs,
+			ManagerCallbacks:    a.getManagerCallbacks(),
+			UsageSampleInterval: a.getUsageSampleInterval(),
 		})
 		if err != nil {
 			config.Runtime.Logger.LogError(fmt.Errorf("error loading modules: %w", err))
 			continue // Must not run the script if the modules are not loaded
 		}
 		defer loader.Close()
+		a.resetReport()
+		a.resetDryRun()
+
+		// Re-runs requested by the control server. The services are stopped, so the script can return, and the script is re-run after
+		// it returns (the Lua state must not be reset while the script is running)
+		rerunArgs := make(chan []string, 1)
+		a.setOnRerunScript(func(newArgs []string) error {
+			select {
+			case rerunArgs <- newArgs:
+			default:
+				return errors.New("there is already a pending re-run")
+			}
+
+			config.Runtime.CloseAllManagers()
+			return nil
+		})
 
 		// Running the script
-		err = config.Runtime.RunFile(config.File, config.Args...)
-		if err != nil {
-			config.Runtime.Logger.LogError(fmt.Errorf("error running script '%s': %w", config.File, err))
+		scriptErr := config.Runtime.RunFile(config.File, config.Args...)
+		if scriptErr != nil {
+			config.Runtime.Logger.LogError(fmt.Errorf("error running script '%s': %w", config.File, scriptErr))
 		}
 
 		// Calling the after run callback
@@ -109,20 +193,38 @@ func (a *App) runLuaFile(config *runLuaConfig) error {
 		}
 
 		// Wait until the TUI is closed or the user selects new arguments
-		for {
-			if !reRunScript && modtui.TuiIsVisible() {
-				time.Sleep(100 * time.Millisecond)
+		for !reRunScript {
+			select {
+			case newArgs := <-rerunArgs:
+				onSelectArgs(newArgs)
 				continue
+			default:
 			}
 
-			break
+			if !a.keepSessionRunning() {
+				break
+			}
+
+			config.Runtime.SleepWithCallbacks(100 * time.Millisecond)
+		}
+
+		// Re-run requested before the control server handler is removed
+		a.setOnRerunScript(nil)
+		select {
+		case newArgs := <-rerunArgs:
+			if !reRunScript {
+				onSelectArgs(newArgs)
+			}
+		default:
 		}
 
 		// Exit if the user closed the TUI without selecting new arguments
 		if !reRunScript {
-			break
+			if a.dryRunPlan != nil {
+				return a.finishDryRun(config.Runtime, scriptErr)
+			}
+
+			return a.finishReport(config.Runtime, config.Name, scriptErr)
 		}
 	}
-
-	return nil
 }
@@ -0,0 +1,39 @@
+// Package luapattern matches texts with Lua patterns outside a Lua state
+package luapattern
+
+import (
+	"strconv"
+
+	"github.com/yuin/gopher-lua/pm"
+)
+
+// Validate checks if a Lua pattern is valid
+func Validate(pattern string) error {
+	_, err := pm.Find(pattern, []byte{}, 0, 1)
+	return err
+}
+
+// Find finds the first match of a Lua pattern in a text (like `string.find`). Returns the captures and whether the text matches. Position
+// captures ('()') are returned as strings with the 1-based position
+func Find(pattern string, text string) ([]string, bool, error) {
+	matches, err := pm.Find(pattern, []byte(text), 0, 1)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(matches) == 0 {
+		return nil, false, nil
+	}
+
+	match := matches[0]
+	captures := make([]string, 0, match.CaptureLength()/2-1)
+	for i := 2; i < match.CaptureLength(); i += 2 {
+		if match.IsPosCapture(i) {
+			captures = append(captures, strconv.Itoa(match.Capture(i)))
+		} else {
+			captures = append(captures, text[match.Capture(i):match.Capture(i+1)])
+		}
+	}
+
+	return captures, true, nil
+}
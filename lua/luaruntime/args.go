@@ -27,3 +27,14 @@ func (r *Runtime) SetOnCurrentScriptArgsChange(f func(args []string)) {
 func (r *Runtime) SetOnScriptAvailableArgsChange(f func(args [][]string)) {
 	r.onSetScriptAvailableArgs = f
 }
+
+// SetScriptParams sets the parameter values of the current script or task (see `project.ParamValues`). The values are strings, integers or
+// booleans
+func (r *Runtime) SetScriptParams(params map[string]any) {
+	r.scriptParams = params
+}
+
+// GetScriptParams gets the parameter values of the current script or task
+func (r *Runtime) GetScriptParams() map[string]any {
+	return r.scriptParams
+}
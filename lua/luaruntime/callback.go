@@ -0,0 +1,93 @@
+package luaruntime
+
+import (
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Callback is a Lua call requested outside the goroutine of the script (e.g. by a service callback). Receives the Lua state of the script
+// and runs in the goroutine of the script, because the Lua state is not thread-safe
+type Callback func(L *lua.LState)
+
+// QueueCallback queues a callback to run in the goroutine of the script. The queued callbacks run while the script waits in a blocking
+// function (see `WaitWithCallbacks`) and after the script returns, while the session is running. Can be called from any goroutine and
+// does not block
+func (r *Runtime) QueueCallback(callback Callback) {
+	r.callbacksMutex.Lock()
+	r.callbacks = append(r.callbacks, callback)
+	r.callbacksMutex.Unlock()
+
+	// Wakes up the script goroutine if it is waiting
+	select {
+	case r.callbacksQueued <- struct{}{}:
+	default:
+	}
+}
+
+// runQueuedCallbacks runs the queued callbacks with the provided Lua state. Must be called in the goroutine of the script
+func (r *Runtime) runQueuedCallbacks(L *lua.LState) {
+	r.callbacksMutex.Lock()
+	callbacks := r.callbacks
+	r.callbacks = nil
+	r.callbacksMutex.Unlock()
+
+	for _, callback := range callbacks {
+		callback(L)
+	}
+}
+
+// clearQueuedCallbacks removes the queued callbacks. Used when the Lua state is closed, because the callbacks use the closed state
+func (r *Runtime) clearQueuedCallbacks() {
+	r.callbacksMutex.Lock()
+	defer r.callbacksMutex.Unlock()
+
+	r.callbacks = nil
+}
+
+// WaitWithCallbacks runs a blocking function in another goroutine and runs the queued callbacks in the current goroutine until it returns.
+// Must be called in the goroutine of the script (e.g. by a Lua function that waits the services), with the Lua state that is running.
+// Returns the error of the function
+func (r *Runtime) WaitWithCallbacks(L *lua.LState, wait func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- wait()
+	}()
+
+	for {
+		r.runQueuedCallbacks(L)
+
+		select {
+		case err := <-done:
+			r.runQueuedCallbacks(L)
+			return err
+		case <-r.callbacksQueued:
+		}
+	}
+}
+
+// SleepWithCallbacks waits the provided duration while running the queued callbacks with the current Lua state. Must be called in the
+// goroutine of the script after the script returns (e.g. while waiting the user to close the TUI)
+func (r *Runtime) SleepWithCallbacks(duration time.Duration) {
+	r.stateMutex.Lock()
+	L := r.luaState
+	r.stateMutex.Unlock()
+
+	if L == nil {
+		time.Sleep(duration)
+		return
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	for {
+		r.runQueuedCallbacks(L)
+
+		select {
+		case <-timer.C:
+			return
+		case <-r.callbacksQueued:
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"fmt"
+
+	"github.com/LucasAVasco/falcula/logparse"
+	"github.com/LucasAVasco/falcula/logsink"
+)
+
+// SetServiceLogFormat sets the format used to parse the logs of a service (see `logparse.New`). The logs of the service are not parsed if
+// the format is empty
+func (l *Logger) SetServiceLogFormat(service string, format string) error {
+	parser, err := logparse.New(format)
+	if err != nil {
+		return fmt.Errorf("error setting log format of service '%s': %w", service, err)
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if parser == nil {
+		delete(l.parsers, service)
+	} else {
+		l.parsers[service] = parser
+	}
+
+	return nil
+}
+
+// ResetServiceLogFormats removes the log formats of all services
+func (l *Logger) ResetServiceLogFormats() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	clear(l.parsers)
+}
+
+// parseServiceLog parses the message of an entry with the log format of its service. Sets the level and time of the entry and returns the
+// message to show in the UI. Must be called with the logger mutex locked
+func (l *Logger) parseServiceLog(entry *logsink.Entry) string {
+	parser := l.parsers[entry.Service]
+	if parser == nil {
+		return entry.Message
+	}
+
+	record, ok := parser.Parse(entry.Message)
+	if !ok {
+		return entry.Message
+	}
+
+	entry.Level = record.Level
+	if !record.Time.IsZero() {
+		entry.Time = record.Time
+	}
+
+	return record.Colorize()
+}
+
+// formatUILine formats an entry to be shown in the UI. The parsed level is colored by its severity
+func formatUILine(entry *logsink.Entry, message string) string {
+	levelColor := logparse.GetLevelColor(entry.Level)
+	if levelColor == nil {
+		return entry.Color.Sprint(entry.Service+" "+entry.GetLevelOrStream()+": ") + message + "\n"
+	}
+
+	return entry.Color.Sprint(entry.Service+" ") + levelColor.Sprint(entry.Level) + entry.Color.Sprint(": ") + message + "\n"
+}
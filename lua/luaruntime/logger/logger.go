@@ -2,72 +2,124 @@
 package logger
 
 import (
+	"errors"
 	"fmt"
 	"os"
-	"strconv"
-	"strings"
+	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/LucasAVasco/falcula/logfile"
+	"github.com/LucasAVasco/falcula/logparse"
+	"github.com/LucasAVasco/falcula/logsink"
 	"github.com/LucasAVasco/falcula/multiplexer"
+	"github.com/LucasAVasco/falcula/redact"
 	"github.com/LucasAVasco/falcula/service/enhanced"
 )
 
-// Logger is a logger that supports multiple log levels and saves the logs to a file
+// Logger is a logger that supports multiple log levels and saves the logs to a file. The service logs are also sent to the configured sinks
 type Logger struct {
+	opts                Options
 	mutex               sync.Mutex
-	logFile             *os.File
+	logFile             *logsink.SyslogFile // Main log file. Opened by the TUI
+	sinks               []logsink.Sink      // Other destinations of the service logs
+	namedSinks          map[string]logsink.Sink
+	parsers             map[string]logparse.Parser       // Parsers of the services logs. Indexed by the service name
+	redactor            *redact.Redactor                 // Masks the secrets of all logs before they are written anywhere
+	outputHandlers      map[string]map[any]OutputHandler // Called with each log line of a service. Indexed by the service name and owner
 	servicesMultiplexer *multiplexer.Multiplexer
 	onServiceLog        func(b []byte) (int, error)
 	onDebugLog          func(b []byte) (int, error)
 	onErrorLog          func(error) (int, error)
+	startTime           time.Time // Time when the logger was created
 }
 
-func New() (*Logger, error) {
-	l := &Logger{}
+// Options is the logger options. All fields are optional
+type Options struct {
+	Dir       string                   // Directory of the session log file. Uses the default log directory if empty
+	Keep      bool                     // Keeps the session log file after closing the logger (it is deleted by default)
+	Retention *logfile.RetentionPolicy // Applied to the log directory when closing the logger. Nothing is rotated if nil
+	Redact    *redact.Config           // Secrets masked in the logs. The environment variables are read when creating the logger
+
+	// Reports the sessions of other running processes (e.g. detached sessions), so they are not rotated. The sessions modified after
+	// creating the logger are always considered active
+	IsSessionActive func(session *logfile.Session) bool
+}
+
+// New creates a new logger. The options are optional
+func New(opts *Options) (*Logger, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	l := &Logger{
+		opts:           *opts,
+		sinks:          make([]logsink.Sink, 0),
+		namedSinks:     make(map[string]logsink.Sink),
+		parsers:        make(map[string]logparse.Parser),
+		redactor:       redact.New(),
+		outputHandlers: make(map[string]map[any]OutputHandler),
+		startTime:      time.Now(),
+	}
+
+	// Secrets
+	if opts.Redact != nil {
+		err := l.redactor.AddConfig(opts.Redact, os.Environ())
+		if err != nil {
+			return nil, fmt.Errorf("error configuring log redaction: %w", err)
+		}
+	}
 
 	// Log file
-	logFile, err := logfile.New()
+	logFile, err := logfile.New(opts.Dir)
 	if err != nil {
 		return nil, fmt.Errorf("error creating log file: %w", err)
 	}
-	l.logFile = logFile
+	l.logFile = logsink.NewSyslogFromFile(logFile)
 
 	// Service multiplexer
 	l.ResetOnServiceLog()
-	l.servicesMultiplexer = multiplexer.New(func(client *multiplexer.Client, b []byte) (int, error) {
+	l.servicesMultiplexer = multiplexer.New(func(entry *multiplexer.Entry) error {
 		l.mutex.Lock()
 		defer l.mutex.Unlock()
 
-		level := client.GetLevel()
-		name := client.GetName()
-		color := client.GetColor()
-		id := client.GetId()
-
-		str := string(b)
+		client := entry.Client
 
-		// Removes the trailing newline
-		if str[len(str)-1] == '\n' {
-			str = str[:len(str)-1]
+		sinkEntry := logsink.Entry{
+			Time:     entry.Time,
+			Service:  client.GetName(),
+			Stream:   client.GetLevel(),
+			ClientId: client.GetId(),
+			Message:  l.redactor.Redact(string(entry.Line)),
+			Color:    client.GetColor(),
 		}
-		lines := strings.SplitSeq(str, "\n")
+		sinkEntry.UILine = formatUILine(&sinkEntry, l.parseServiceLog(&sinkEntry))
+
+		l.callServiceOutputHandlers(&sinkEntry)
 
-		for line := range lines {
-			// Uses the 'syslog_log' format recognized by 'Lnav'
-			logFileLine := time.Now().Format(time.RFC3339) + " " + name + " " + level + "[" + strconv.Itoa(int(id)) + "]: " + line + "\n"
+		// Sinks
+		errs := []error{}
 
-			_, err := l.logFile.Write([]byte(logFileLine))
+		err := l.logFile.Write(&sinkEntry)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error writing to log file: %w", err))
+		}
+
+		for _, sink := range l.sinks {
+			err := sink.Write(&sinkEntry)
 			if err != nil {
-				return 0, err
+				errs = append(errs, fmt.Errorf("error writing to sink: %w", err))
 			}
-
-			// Adds the log to the multiplexer
-			uiLine := color.Sprint(name+" "+level+": ") + line + "\n"
-			l.onServiceLog([]byte(uiLine))
 		}
 
-		return len(b), nil
+		// Adds the log to the multiplexer
+		l.onServiceLog([]byte(sinkEntry.UILine))
+
+		return errors.Join(errs...)
+	}, &multiplexer.Options{
+		OnError: func(err error) {
+			l.LogError(fmt.Errorf("error writing service log: %w", err))
+		},
 	})
 
 	// Debug log
@@ -81,18 +133,26 @@ func New() (*Logger, error) {
 
 // Close closes the logger. Can be called multiple times
 func (l *Logger) Close() error {
-	// Multiplexer
+	// Multiplexer. Waits until all queued service logs are written
 	if l.servicesMultiplexer != nil {
 		err := l.servicesMultiplexer.Close()
 		if err != nil {
 			return fmt.Errorf("error closing multiplexer: %w", err)
 		}
+
+		if dropped := l.servicesMultiplexer.GetDroppedLines(); dropped > 0 {
+			l.LogDebug(fmt.Sprintf("%d service log lines were dropped because the log queue was full\n", dropped))
+		}
+
 		l.servicesMultiplexer = nil
 	}
 
+	// Sinks
+	l.RemoveAllSinks()
+
 	// Log file
 	if l.logFile != nil {
-		logFileName := l.logFile.Name()
+		logFileName := l.logFile.GetPath()
 
 		// Closing
 		err := l.logFile.Close()
@@ -101,9 +161,19 @@ func (l *Logger) Close() error {
 		}
 
 		// Deletes the file
-		err = os.Remove(logFileName)
-		if err != nil {
-			return fmt.Errorf("error removing log file: %w", err)
+		if !l.opts.Keep {
+			err = os.Remove(logFileName)
+			if err != nil {
+				return fmt.Errorf("error removing log file: %w", err)
+			}
+		}
+
+		// Removes and compresses the old sessions
+		if l.opts.Retention != nil {
+			err = logfile.Rotate(filepath.Dir(logFileName), l.opts.Retention, logFileName, l.isSessionActive)
+			if err != nil {
+				return fmt.Errorf("error rotating log directory: %w", err)
+			}
 		}
 
 		l.logFile = nil
@@ -112,15 +182,38 @@ func (l *Logger) Close() error {
 	return nil
 }
 
+// isSessionActive returns true if a session of the log directory may be in use by another running process
+func (l *Logger) isSessionActive(session *logfile.Session) bool {
+	if !session.ModTime.Before(l.startTime) {
+		return true
+	}
+
+	return l.opts.IsSessionActive != nil && l.opts.IsSessionActive(session)
+}
+
 // GetLogFilePath returns the path to the log file
 func (l *Logger) GetLogFilePath() string {
-	return l.logFile.Name()
+	return l.logFile.GetPath()
 }
 
 func (l *Logger) GetServicesMultiplexer() *multiplexer.Multiplexer {
 	return l.servicesMultiplexer
 }
 
+// SetServicesLogPolicy sets the behavior of the services multiplexer when its queue is full
+func (l *Logger) SetServicesLogPolicy(policy multiplexer.Policy) {
+	l.servicesMultiplexer.SetPolicy(policy)
+}
+
+// GetDroppedServiceLines returns the number of service log lines dropped because the queue of the services multiplexer was full
+func (l *Logger) GetDroppedServiceLines() uint64 {
+	if l.servicesMultiplexer == nil {
+		return 0
+	}
+
+	return l.servicesMultiplexer.GetDroppedLines()
+}
+
 // SetOnServiceLog sets the handler for the service log
 func (l *Logger) SetOnServiceLog(handler func(b []byte) (int, error)) {
 	l.mutex.Lock()
@@ -137,11 +230,11 @@ func (l *Logger) ResetOnServiceLog() {
 }
 
 // LogService logs a message to the service log
-func (l *Logger) LogService(svc enhanced.EnhancedService, message string) (int, error) {
+func (l *Logger) LogService(svc *enhanced.EnhancedService, message string) (int, error) {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 
-	return l.onServiceLog([]byte(message))
+	return l.onServiceLog([]byte(l.redactor.Redact(message)))
 }
 
 // SetOnDebugLog sets the handler for the debug log
@@ -164,7 +257,7 @@ func (l *Logger) LogDebug(message string) (int, error) {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 
-	return l.onDebugLog([]byte(message))
+	return l.onDebugLog([]byte(l.redactor.Redact(message)))
 }
 
 // SetOnErrorLog sets the handler for the error log
@@ -187,13 +280,13 @@ func (l *Logger) LogError(err error) (int, error) {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 
-	return l.onErrorLog(err)
+	return l.onErrorLog(l.redactError(err))
 }
 
 // GetOnErrorWithoutReturn returns a function that logs an error and discards the return value
 func (l *Logger) GetOnErrorWithoutReturn() func(error) {
 	return func(err error) {
-		l.onErrorLog(err)
+		l.onErrorLog(l.redactError(err))
 	}
 }
 
@@ -220,3 +313,23 @@ func (l *Logger) ResetLoggers() {
 	l.ResetOnDebugLog()
 	l.ResetOnErrorLog()
 }
+
+// GetRedactor returns the redactor that masks the secrets of all logs. New secrets can be registered at any time
+func (l *Logger) GetRedactor() *redact.Redactor {
+	return l.redactor
+}
+
+// redactError masks the secrets of an error message. Returns the same error if it has no secrets
+func (l *Logger) redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	message := err.Error()
+	redacted := l.redactor.Redact(message)
+	if redacted == message {
+		return err
+	}
+
+	return errors.New(redacted)
+}
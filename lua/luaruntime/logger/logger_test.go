@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/LucasAVasco/falcula/logsink"
+	"github.com/LucasAVasco/falcula/redact"
+	"github.com/fatih/color"
+)
+
+func TestRedactSecretSplitAcrossWrites(t *testing.T) {
+	const secret = "s3cr3t-t0k3n-value"
+
+	tests := []struct {
+		name     string
+		redact   *redact.Config
+		register bool // Registers the secret as a value
+		env      string
+	}{
+		{
+			name:     "registered value",
+			register: true,
+		},
+		{
+			name:   "environment variable",
+			redact: &redact.Config{Env: []string{"FALCULA_TEST_*_TOKEN"}},
+			env:    "FALCULA_TEST_API_TOKEN",
+		},
+		{
+			name:   "pattern",
+			redact: &redact.Config{Patterns: []string{`token=(\S+)`}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.env != "" {
+				t.Setenv(test.env, secret)
+			}
+
+			l, err := New(&Options{Dir: t.TempDir(), Keep: true, Redact: test.redact})
+			if err != nil {
+				t.Fatalf("error creating logger: %v", err)
+			}
+
+			if test.register {
+				l.GetRedactor().AddValue(secret)
+			}
+
+			ring := logsink.NewRing(10)
+			err = l.AddSink("ring", ring)
+			if err != nil {
+				t.Fatalf("error adding sink: %v", err)
+			}
+
+			uiLines := []string{}
+			uiMutex := sync.Mutex{}
+			l.SetOnServiceLog(func(b []byte) (int, error) {
+				uiMutex.Lock()
+				defer uiMutex.Unlock()
+
+				uiLines = append(uiLines, string(b))
+				return len(b), nil
+			})
+
+			// The secret is split across two writes of the service output
+			client := l.GetServicesMultiplexer().NewClient("api", "stdout", color.New(color.FgBlue))
+			for _, chunk := range []string{"connecting with token=" + secret[:7], secret[7:] + " done\n"} {
+				_, err := client.Write([]byte(chunk))
+				if err != nil {
+					t.Fatalf("error writing to client: %v", err)
+				}
+			}
+
+			logFilePath := l.GetLogFilePath()
+			err = l.Close()
+			if err != nil {
+				t.Fatalf("error closing logger: %v", err)
+			}
+
+			// Sink
+			entries := ring.GetEntries()
+			if len(entries) != 1 {
+				t.Fatalf("sink received %d entries, expected 1", len(entries))
+			}
+			assertRedacted(t, "sink", entries[0].Message, secret)
+
+			// Log file
+			content, err := os.ReadFile(logFilePath)
+			if err != nil {
+				t.Fatalf("error reading log file: %v", err)
+			}
+			assertRedacted(t, "log file", string(content), secret)
+
+			// UI
+			if len(uiLines) != 1 {
+				t.Fatalf("UI received %d lines, expected 1", len(uiLines))
+			}
+			assertRedacted(t, "UI", uiLines[0], secret)
+		})
+	}
+}
+
+// assertRedacted checks that the text has the mask and has no part of the secret
+func assertRedacted(t *testing.T, destination string, text string, secret string) {
+	t.Helper()
+
+	if !strings.Contains(text, redact.Mask) {
+		t.Errorf("%s output %q is not redacted", destination, text)
+	}
+
+	for _, part := range []string{secret, secret[:7], secret[7:]} {
+		if strings.Contains(text, part) {
+			t.Errorf("%s output %q has the secret part %q", destination, text, part)
+		}
+	}
+}
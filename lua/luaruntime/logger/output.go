@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"github.com/LucasAVasco/falcula/logsink"
+)
+
+// OutputHandler is called with each log line of a service, after the secrets are masked and the line is parsed. It is called by the
+// goroutine that writes the logs, so it must not block (long operations must run in another goroutine) and must not change the entry
+type OutputHandler func(entry *logsink.Entry)
+
+// SetServiceOutputHandler sets the handler called with each log line of a service. The owner (e.g. the service manager) identifies the
+// handler with the service name, so services with the same name of different owners do not replace the handlers of each other (the log
+// lines only have the service name, so all of them are called). Removes the handler if it is nil
+func (l *Logger) SetServiceOutputHandler(owner any, service string, handler OutputHandler) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if handler == nil {
+		delete(l.outputHandlers[service], owner)
+		if len(l.outputHandlers[service]) == 0 {
+			delete(l.outputHandlers, service)
+		}
+		return
+	}
+
+	if l.outputHandlers[service] == nil {
+		l.outputHandlers[service] = make(map[any]OutputHandler)
+	}
+	l.outputHandlers[service][owner] = handler
+}
+
+// RemoveServiceOutputHandlers removes the output handlers of all services of an owner (e.g. when the service manager is closed)
+func (l *Logger) RemoveServiceOutputHandlers(owner any) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for service, handlers := range l.outputHandlers {
+		delete(handlers, owner)
+		if len(handlers) == 0 {
+			delete(l.outputHandlers, service)
+		}
+	}
+}
+
+// ResetServiceOutputHandlers removes the output handlers of all services
+func (l *Logger) ResetServiceOutputHandlers() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	clear(l.outputHandlers)
+}
+
+// callServiceOutputHandlers calls the output handlers of the service of the entry. Must be called with the logger mutex locked
+func (l *Logger) callServiceOutputHandlers(entry *logsink.Entry) {
+	for _, handler := range l.outputHandlers[entry.Service] {
+		handler(entry)
+	}
+}
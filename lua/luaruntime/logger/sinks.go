@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/LucasAVasco/falcula/logsink"
+)
+
+// AddSink adds a destination to the service logs. The name is optional and can be used to get the sink with `GetSink`
+func (l *Logger) AddSink(name string, sink logsink.Sink) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if name != "" {
+		if _, ok := l.namedSinks[name]; ok {
+			return fmt.Errorf("there is already a sink named '%s'", name)
+		}
+		l.namedSinks[name] = sink
+	}
+
+	l.sinks = append(l.sinks, sink)
+	return nil
+}
+
+// AddSinkFromConfig creates a sink from its configuration and adds it to the logger. Returns the created sink
+func (l *Logger) AddSinkFromConfig(config *logsink.Config) (logsink.Sink, error) {
+	sink, err := logsink.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating sink: %w", err)
+	}
+
+	err = l.AddSink(config.Name, sink)
+	if err != nil {
+		sink.Close()
+		return nil, fmt.Errorf("error adding sink: %w", err)
+	}
+
+	return sink, nil
+}
+
+// GetSink returns the sink with the provided name or nil if there is no sink with this name. If the sink has a filter, returns the filtered
+// sink instead of the filter
+func (l *Logger) GetSink(name string) logsink.Sink {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	sink := l.namedSinks[name]
+	if filter, ok := sink.(*logsink.Filter); ok {
+		return filter.GetSink()
+	}
+
+	return sink
+}
+
+// RemoveSink removes a sink from the logger and closes it
+func (l *Logger) RemoveSink(sink logsink.Sink) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	index := slices.Index(l.sinks, sink)
+	if index == -1 {
+		return fmt.Errorf("sink not found")
+	}
+	l.sinks = slices.Delete(l.sinks, index, index+1)
+
+	for name, namedSink := range l.namedSinks {
+		if namedSink == sink {
+			delete(l.namedSinks, name)
+		}
+	}
+
+	return sink.Close()
+}
+
+// RemoveAllSinks removes all sinks added with `AddSink`. Errors are logged in the error log
+func (l *Logger) RemoveAllSinks() {
+	l.mutex.Lock()
+	sinks := slices.Clone(l.sinks)
+	l.mutex.Unlock()
+
+	for _, sink := range sinks {
+		err := l.RemoveSink(sink)
+		if err != nil {
+			l.LogError(fmt.Errorf("error removing log sink: %w", err))
+		}
+	}
+}
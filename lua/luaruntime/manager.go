@@ -9,16 +9,25 @@ import (
 
 // AddManager adds a service manager to the runtime list
 func (r *Runtime) AddManager(manager *manager.Manager) {
+	r.managersMutex.Lock()
+	defer r.managersMutex.Unlock()
+
 	r.managers = append(r.managers, manager)
 }
 
-// GetManagers gets the list of service managers in the runtime list
+// GetManagers gets a copy of the list of service managers in the runtime list
 func (r *Runtime) GetManagers() []*manager.Manager {
-	return r.managers
+	r.managersMutex.Lock()
+	defer r.managersMutex.Unlock()
+
+	return slices.Clone(r.managers)
 }
 
 // RemoveManager removes a service manager from the runtime list
 func (r *Runtime) RemoveManager(man *manager.Manager) {
+	r.managersMutex.Lock()
+	defer r.managersMutex.Unlock()
+
 	index := slices.Index(r.managers, man)
 	r.managers = slices.Delete(r.managers, index, index+1)
 }
@@ -27,14 +36,17 @@ func (r *Runtime) closeAllManagersWithoutLock() {
 	r.onSetScriptCurrentArgs([]string{})
 	r.onSetScriptAvailableArgs([][]string{})
 
-	for _, man := range r.managers {
+	r.managersMutex.Lock()
+	managers := r.managers
+	r.managers = make([]*manager.Manager, 0)
+	r.managersMutex.Unlock()
+
+	for _, man := range managers {
 		err := man.Close(true, nil)
 		if err != nil {
 			r.Logger.LogError(fmt.Errorf("error closing manager '%s': %v", man.GetName(), err.Error()))
 		}
 	}
-
-	r.managers = make([]*manager.Manager, 0)
 }
 
 // CloseAllManagers closes all service managers. Errors are logged in the logger
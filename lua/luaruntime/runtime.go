@@ -7,6 +7,7 @@ import (
 
 	"github.com/LucasAVasco/falcula/lua/luaruntime/ioredirect"
 	"github.com/LucasAVasco/falcula/lua/luaruntime/logger"
+	"github.com/LucasAVasco/falcula/process"
 	"github.com/LucasAVasco/falcula/service/manager"
 	lua "github.com/yuin/gopher-lua"
 )
@@ -19,22 +20,44 @@ type Runtime struct {
 	stateMutex       sync.Mutex
 	lastExecutedFile string
 
+	// Lua calls requested by other goroutines. Run in the script goroutine (see `QueueCallback`)
+	callbacks       []Callback
+	callbacksMutex  sync.Mutex
+	callbacksQueued chan struct{} // Receives a value when a callback is queued
+
 	// All logs are sent to this logger
 	Logger *logger.Logger
 
+	processRecorder process.Recorder
+
 	// Service managers
-	managers []*manager.Manager
+	managers      []*manager.Manager
+	managersMutex sync.Mutex // The managers are also read by the control server
 
 	// Script arguments
 	scriptCurrentArgs        []string
 	scriptAvailableArgs      [][]string
+	scriptParams             map[string]any
 	onSetScriptCurrentArgs   func(args []string)
 	onSetScriptAvailableArgs func(args [][]string)
 }
 
-func New() (*Runtime, error) {
+// Options is the runtime options. All fields are optional
+type Options struct {
+	Logger          *logger.Options  // Options of the runtime logger
+	ProcessRecorder process.Recorder // Records the processes of the services instead of spawning them (dry run)
+}
+
+// New creates a new runtime. The options are optional
+func New(opts *Options) (*Runtime, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
 	r := Runtime{
-		managers: make([]*manager.Manager, 0),
+		managers:        make([]*manager.Manager, 0),
+		processRecorder: opts.ProcessRecorder,
+		callbacksQueued: make(chan struct{}, 1),
 	}
 
 	// Default callbacks
@@ -43,7 +66,7 @@ func New() (*Runtime, error) {
 
 	// Logger
 	var err error
-	r.Logger, err = logger.New()
+	r.Logger, err = logger.New(opts.Logger)
 	if err != nil {
 		return nil, fmt.Errorf("error creating logger: %w", err)
 	}
@@ -51,9 +74,15 @@ func New() (*Runtime, error) {
 	return &r, nil
 }
 
-// Close closes the runtime. Can be called multiple times
+// Close closes the runtime and its logger. Can be called multiple times
 func (r *Runtime) Close() error {
 	r.CloseLuaState()
+
+	err := r.Logger.Close()
+	if err != nil {
+		return fmt.Errorf("error closing logger: %w", err)
+	}
+
 	return nil
 }
 
@@ -63,6 +92,7 @@ func (r *Runtime) closeLuaStateWithoutLock() {
 	}
 
 	r.closeAllManagersWithoutLock()
+	r.clearQueuedCallbacks()
 	r.luaState.Close()
 	r.luaState = nil
 }
@@ -108,6 +138,11 @@ func (r *Runtime) GetLastExecutedFile() string {
 	return r.lastExecutedFile
 }
 
+// GetProcessRecorder returns the recorder of the service processes (dry run). Returns nil if the processes are spawned
+func (r *Runtime) GetProcessRecorder() process.Recorder {
+	return r.processRecorder
+}
+
 // Run runs a Lua code with the given arguments. It updates the current arguments, but does not update the last executed file because there
 // is no file
 func (r *Runtime) Run(luaCode string, args ...string) error {
@@ -2,15 +2,18 @@ package modules
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/LucasAVasco/falcula/lua/modules/modcmd"
 	"github.com/LucasAVasco/falcula/lua/modules/moddockercompose"
 	"github.com/LucasAVasco/falcula/lua/modules/modfalcula"
 	"github.com/LucasAVasco/falcula/lua/modules/modinspect"
 	"github.com/LucasAVasco/falcula/lua/modules/modjson"
+	"github.com/LucasAVasco/falcula/lua/modules/modlog"
 	"github.com/LucasAVasco/falcula/lua/modules/modmanager"
 	"github.com/LucasAVasco/falcula/lua/modules/modpath"
 	"github.com/LucasAVasco/falcula/lua/modules/modprocess"
+	"github.com/LucasAVasco/falcula/lua/modules/modsecret"
 	"github.com/LucasAVasco/falcula/lua/modules/modtbl"
 	"github.com/LucasAVasco/falcula/lua/modules/modtemplate"
 	"github.com/LucasAVasco/falcula/lua/modules/modtui"
@@ -23,6 +26,12 @@ import (
 type AllModulesLoaderOptions struct {
 	RawMode      bool                // Disables the TUI. Runs non-interactive
 	OnSelectArgs func(args []string) // Called when the user selects the arguments to re-run the script
+
+	// Also called when the managers or services change, after the TUI is updated (e.g. by the web dashboard). Optional, the fields can also
+	// be nil
+	ManagerCallbacks *modmanager.Callbacks
+
+	UsageSampleInterval time.Duration // Interval between the resource usage samples of the services. Disabled if zero
 }
 
 // LoadAllModules loads all available modules
@@ -35,7 +44,7 @@ func (l *Loader) LoadAllModules(config *AllModulesLoaderOptions) error {
 	tuiModule := modtui.New(&tuiModuleConfig)
 
 	// Manager module
-	managerMod := modmanager.New(l.getManagerCallbacks(tuiModule))
+	managerMod := modmanager.New(l.getManagerCallbacks(tuiModule, config.ManagerCallbacks), config.UsageSampleInterval)
 	modCmd := modcmd.New()
 
 	// Runtime callbacks
@@ -56,6 +65,8 @@ func (l *Loader) LoadAllModules(config *AllModulesLoaderOptions) error {
 	l.LoadModule("falcula", modfalcula.New())
 	l.LoadModuleFromFunction("falcula.inspect", modinspect.LoadFunction)
 	l.LoadModule("falcula.json", modjson.New())
+	l.LoadModule("falcula.log", modlog.New())
+	l.LoadModule("falcula.secret", modsecret.New())
 	l.LoadModule("falcula.yaml", modyaml.New())
 	l.LoadModule("falcula.tbl", modtbl.New())
 	l.LoadModule("falcula.manager", managerMod)
@@ -73,9 +84,12 @@ func (l *Loader) LoadAllModules(config *AllModulesLoaderOptions) error {
 	return nil
 }
 
-// getManagerCallbacks gets the callbacks for the manager module
-func (l *Loader) getManagerCallbacks(tuiModule *modtui.Module) *modmanager.Callbacks {
+// getManagerCallbacks gets the callbacks for the manager module. The extra callbacks are optional
+func (l *Loader) getManagerCallbacks(tuiModule *modtui.Module, extra *modmanager.Callbacks) *modmanager.Callbacks {
 	managerCallbacks := modmanager.Callbacks{}
+	if extra == nil {
+		extra = &modmanager.Callbacks{}
+	}
 
 	logError := func(err error) {
 		l.runtime.Logger.LogError(err)
@@ -90,6 +104,10 @@ func (l *Loader) getManagerCallbacks(tuiModule *modtui.Module) *modmanager.Callb
 				logError(fmt.Errorf("error adding manager: %w", err))
 			}
 		}
+
+		if extra.OnNewManager != nil {
+			extra.OnNewManager(man)
+		}
 	}
 
 	managerCallbacks.OnDeleteManager = func(man *manager.Manager) {
@@ -101,6 +119,10 @@ func (l *Loader) getManagerCallbacks(tuiModule *modtui.Module) *modmanager.Callb
 				logError(fmt.Errorf("error removing manager: %w", err))
 			}
 		}
+
+		if extra.OnDeleteManager != nil {
+			extra.OnDeleteManager(man)
+		}
 	}
 
 	managerCallbacks.OnAddService = func(man *manager.Manager, svc *enhanced.EnhancedService) {
@@ -110,6 +132,10 @@ func (l *Loader) getManagerCallbacks(tuiModule *modtui.Module) *modmanager.Callb
 				logError(fmt.Errorf("error adding service: %w", err))
 			}
 		}
+
+		if extra.OnAddService != nil {
+			extra.OnAddService(man, svc)
+		}
 	}
 
 	managerCallbacks.OnServiceStatusChanged = func(man *manager.Manager, svc *enhanced.EnhancedService) {
@@ -119,6 +145,23 @@ func (l *Loader) getManagerCallbacks(tuiModule *modtui.Module) *modmanager.Callb
 				logError(fmt.Errorf("error updating service status: %w", err))
 			}
 		}
+
+		if extra.OnServiceStatusChanged != nil {
+			extra.OnServiceStatusChanged(man, svc)
+		}
+	}
+
+	managerCallbacks.OnServiceUsageSampled = func(man *manager.Manager, svc *enhanced.EnhancedService) {
+		if tui := tuiModule.GetTui(); tui != nil {
+			err := tui.UpdateServiceStatusInSidebar(man, svc)
+			if err != nil {
+				logError(fmt.Errorf("error updating service resource usage: %w", err))
+			}
+		}
+
+		if extra.OnServiceUsageSampled != nil {
+			extra.OnServiceUsageSampled(man, svc)
+		}
 	}
 
 	return &managerCallbacks
@@ -2,6 +2,8 @@
 package modcmd
 
 import (
+	"fmt"
+
 	"github.com/LucasAVasco/falcula/lua/luatable"
 	"github.com/LucasAVasco/falcula/lua/modules/base"
 
@@ -37,6 +39,19 @@ func (m *Module) SetCurrentScriptArgs(args []string) {
 func (m *Module) Loader(L *lua.LState, name string, mod *lua.LTable) error {
 	L.SetField(mod, "args", &m.cmdArgs)
 
+	params := L.NewTable()
+	for name, value := range m.Config.Runtime.GetScriptParams() {
+		switch value := value.(type) {
+		case int:
+			params.RawSetString(name, lua.LNumber(value))
+		case bool:
+			params.RawSetString(name, lua.LBool(value))
+		default:
+			params.RawSetString(name, lua.LString(fmt.Sprint(value)))
+		}
+	}
+	L.SetField(mod, "params", params)
+
 	L.SetField(mod, "set_available_args", L.NewFunction(func(L *lua.LState) int {
 		argsList := L.ToTable(1)
 		availableCmdArgs := [][]string{}
@@ -35,6 +35,7 @@ func (l *Loader) Loader(L *lua.LState, name string, mod *lua.LTable) error {
 			config := dockercompose.ProviderConfig{}
 			config.Name = providerName
 			config.Multiplexer = l.Config.Runtime.Logger.GetServicesMultiplexer()
+			config.Recorder = l.Config.Runtime.GetProcessRecorder()
 			err := maplua.Unmarshal(L.OptTable(4, L.NewTable()), &config.Opts)
 			if err != nil {
 				return fmt.Errorf("error getting provider configuration: %w", err)
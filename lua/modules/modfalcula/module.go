@@ -4,6 +4,7 @@ package modfalcula
 import (
 	"github.com/LucasAVasco/falcula/lua/luaerror"
 	"github.com/LucasAVasco/falcula/lua/modules/base"
+	"github.com/LucasAVasco/falcula/multiplexer"
 
 	lua "github.com/yuin/gopher-lua"
 )
@@ -24,6 +25,21 @@ func (m *Module) Loader(L *lua.LState, name string, mod *lua.LTable) error {
 			luaerror.ConfigureAbortOnError(abortOnError)
 			return 0
 		},
+
+		"configure_log_policy": func(L *lua.LState) int {
+			policy, err := multiplexer.ParsePolicy(L.ToString(1))
+			if err != nil {
+				return luaerror.Push(L, 0, err)
+			}
+
+			m.Config.Runtime.Logger.SetServicesLogPolicy(policy)
+			return 0
+		},
+
+		"get_dropped_log_lines": func(L *lua.LState) int {
+			L.Push(lua.LNumber(m.Config.Runtime.Logger.GetDroppedServiceLines()))
+			return 1
+		},
 	})
 
 	return nil
@@ -0,0 +1,98 @@
+// Package modlog is a module that provides functions to configure the destinations (sinks) of the service logs
+package modlog
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/LucasAVasco/falcula/logsink"
+	"github.com/LucasAVasco/falcula/lua/luaerror"
+	"github.com/LucasAVasco/falcula/lua/luapath"
+	"github.com/LucasAVasco/falcula/lua/maplua"
+	"github.com/LucasAVasco/falcula/lua/modules/base"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Module is a module that provides functions to configure the destinations (sinks) of the service logs
+type Module struct {
+	base.BaseModule
+
+	sinks []logsink.Sink // Sinks added by this module. Removed when the module is closed
+}
+
+func New() *Module {
+	return &Module{
+		sinks: make([]logsink.Sink, 0),
+	}
+}
+
+func (m *Module) Loader(L *lua.LState, name string, mod *lua.LTable) error {
+	L.SetFuncs(mod, map[string]lua.LGFunction{
+		"add_sink": func(L *lua.LState) int {
+			config := logsink.Config{}
+			err := maplua.Unmarshal(L.CheckTable(1), &config)
+			if err != nil {
+				return luaerror.Push(L, 0, fmt.Errorf("error parsing sink configuration: %w", err))
+			}
+
+			// Relative paths are relative to the current Lua file
+			if config.Path != "" {
+				config.Path, err = luapath.GetAbs(L, config.Path)
+				if err != nil {
+					return luaerror.Push(L, 0, fmt.Errorf("error getting absolute path of sink: %w", err))
+				}
+			}
+
+			sink, err := m.Config.Runtime.Logger.AddSinkFromConfig(&config)
+			if err != nil {
+				return luaerror.Push(L, 0, err)
+			}
+			m.sinks = append(m.sinks, sink)
+
+			return 0
+		},
+
+		"get_entries": func(L *lua.LState) int {
+			sinkName := L.CheckString(1)
+
+			ring, ok := m.Config.Runtime.Logger.GetSink(sinkName).(*logsink.Ring)
+			if !ok {
+				return luaerror.Push(L, 1, fmt.Errorf("there is no ring sink named '%s'", sinkName))
+			}
+
+			entries := L.NewTable()
+			for _, entry := range ring.GetEntries() {
+				entryTable := L.NewTable()
+				L.SetField(entryTable, "time", lua.LString(entry.Time.Format(time.RFC3339Nano)))
+				L.SetField(entryTable, "service", lua.LString(entry.Service))
+				L.SetField(entryTable, "stream", lua.LString(entry.Stream))
+				if entry.Level != "" {
+					L.SetField(entryTable, "level", lua.LString(entry.Level))
+				}
+				L.SetField(entryTable, "message", lua.LString(entry.Message))
+				entries.Append(entryTable)
+			}
+
+			L.Push(entries)
+			return 1
+		},
+	})
+
+	return nil
+}
+
+// Close removes the sinks added by this module. Can be called multiple times
+func (m *Module) Close() error {
+	errs := []error{}
+	for _, sink := range m.sinks {
+		err := m.Config.Runtime.Logger.RemoveSink(sink)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error removing log sink: %w", err))
+		}
+	}
+
+	m.sinks = make([]logsink.Sink, 0)
+	return errors.Join(errs...)
+}
@@ -1,6 +1,8 @@
 package modmanager
 
 import (
+	"fmt"
+
 	"github.com/LucasAVasco/falcula/lua/luaclass"
 	"github.com/LucasAVasco/falcula/lua/luadata"
 	"github.com/LucasAVasco/falcula/lua/luaerror"
@@ -26,22 +28,51 @@ func (m *Module) returnErrorMessage(L *lua.LState, err error) int {
 	return luaerror.Push(L, 0, err)
 }
 
-func (m *Module) GetMethods() map[string]lua.LGFunction {
-	createServiceCallbacks := func(man *manager.Manager) *enhanced.Callbacks {
-		return &enhanced.Callbacks{
-			OnServiceStatusChanged: func(svc *enhanced.EnhancedService) {
-				m.callbacks.OnServiceStatusChanged(man, svc)
-			},
-		}
+// wait runs a blocking operation of a manager. The Lua functions of the output triggers run while it waits
+func (m *Module) wait(L *lua.LState, operation func() error) error {
+	return m.Config.Runtime.WaitWithCallbacks(L, operation)
+}
+
+// addService adds a service to a manager. Configures the log format and the output triggers of the service
+func (m *Module) addService(man *manager.Manager, svc iface.Service) error {
+	opts := svc.GetOpts()
+	err := validateOutputTriggers(opts.OnOutput)
+	if err != nil {
+		return fmt.Errorf("error validating output triggers of service '%s': %w", svc.GetName(), err)
+	}
+
+	thresholds, err := newThresholds(opts.Thresholds)
+	if err != nil {
+		return fmt.Errorf("error validating resource thresholds of service '%s': %w", svc.GetName(), err)
+	}
+
+	err = m.Config.Runtime.Logger.SetServiceLogFormat(svc.GetName(), opts.LogFormat)
+	if err != nil {
+		return err
+	}
+
+	enhancedService := man.AddService(svc, &enhanced.Callbacks{
+		OnServiceStatusChanged: func(svc *enhanced.EnhancedService) {
+			m.callbacks.OnServiceStatusChanged(man, svc)
+		},
+	})
+
+	if len(opts.OnOutput) > 0 {
+		m.Config.Runtime.Logger.SetServiceOutputHandler(man, svc.GetName(), m.newOutputHandler(enhancedService, opts.OnOutput))
 	}
 
+	m.sampleService(man, enhancedService, thresholds)
+
+	m.callbacks.OnAddService(man, enhancedService)
+	return nil
+}
+
+func (m *Module) GetMethods() map[string]lua.LGFunction {
 	return map[string]lua.LGFunction{
 		"add_service": func(L *lua.LState) int {
 			man := getManager(L)
 			svc := luadata.GetValueFromArgs(L, 2).(iface.Service)
-			enhancedService := man.AddService(svc, createServiceCallbacks(man))
-			m.callbacks.OnAddService(man, enhancedService)
-			return 0
+			return m.returnErrorMessage(L, m.addService(man, svc))
 		},
 
 		"add_services": func(L *lua.LState) int {
@@ -50,13 +81,28 @@ func (m *Module) GetMethods() map[string]lua.LGFunction {
 
 			for i := 0; i < services.Len(); i++ {
 				svc := services.RawGetInt(i + 1).(*lua.LUserData).Value.(iface.Service)
-				enhancedService := man.AddService(svc, createServiceCallbacks(man))
-				m.callbacks.OnAddService(man, enhancedService)
+				err := m.addService(man, svc)
+				if err != nil {
+					return m.returnErrorMessage(L, err)
+				}
 			}
 
 			return 0
 		},
 
+		"get_service": func(L *lua.LState) int {
+			man := getManager(L)
+			name := L.CheckString(2)
+
+			svc := man.GetServiceByName(name)
+			if svc == nil {
+				return luaerror.Push(L, 1, fmt.Errorf("service '%s' not found in manager '%s'", name, man.GetName()))
+			}
+
+			L.Push(m.newServiceTable(L, svc))
+			return 1
+		},
+
 		"start_prepare": func(L *lua.LState) int {
 			man := getManager(L)
 			man.StartPrepare(nil)
@@ -65,18 +111,18 @@ func (m *Module) GetMethods() map[string]lua.LGFunction {
 
 		"wait_prepare": func(L *lua.LState) int {
 			man := getManager(L)
-			return m.returnErrorMessage(L, man.WaitPrepare(nil))
+			return m.returnErrorMessage(L, m.wait(L, func() error { return man.WaitPrepare(nil) }))
 		},
 
 		"prepare": func(L *lua.LState) int {
 			man := getManager(L)
-			return m.returnErrorMessage(L, man.Prepare(nil).Wait())
+			return m.returnErrorMessage(L, m.wait(L, man.Prepare(nil).Wait))
 		},
 
 		"abort_prepare": func(L *lua.LState) int {
 			man := getManager(L)
 			force := L.OptBool(2, false)
-			return m.returnErrorMessage(L, man.AbortPrepare(force, nil).Wait())
+			return m.returnErrorMessage(L, m.wait(L, man.AbortPrepare(force, nil).Wait))
 		},
 
 		"start": func(L *lua.LState) int {
@@ -87,30 +133,32 @@ func (m *Module) GetMethods() map[string]lua.LGFunction {
 
 		"wait": func(L *lua.LState) int {
 			man := getManager(L)
-			return m.returnErrorMessage(L, man.Wait(nil))
+			return m.returnErrorMessage(L, m.wait(L, func() error { return man.Wait(nil) }))
 		},
 
 		"run": func(L *lua.LState) int {
 			man := getManager(L)
-			return m.returnErrorMessage(L, man.Run(nil))
+			return m.returnErrorMessage(L, m.wait(L, func() error { return man.Run(nil) }))
 		},
 
 		"run_serial": func(L *lua.LState) int {
 			man := getManager(L)
-			return m.returnErrorMessage(L, man.RunSerial(nil, nil))
+			return m.returnErrorMessage(L, m.wait(L, func() error { return man.RunSerial(nil, nil) }))
 		},
 
 		"stop": func(L *lua.LState) int {
 			man := getManager(L)
 			force := L.OptBool(2, false)
-			return m.returnErrorMessage(L, man.Stop(force, nil).Wait())
+			return m.returnErrorMessage(L, m.wait(L, man.Stop(force, nil).Wait))
 		},
 
 		"close": func(L *lua.LState) int {
 			man := getManager(L)
 			force := L.OptBool(2, false)
 			m.callbacks.OnDeleteManager(man)
-			return m.returnErrorMessage(L, man.Close(force, nil))
+			err := m.wait(L, func() error { return man.Close(force, nil) })
+			m.Config.Runtime.Logger.RemoveServiceOutputHandlers(man)
+			return m.returnErrorMessage(L, err)
 		},
 	}
 }
@@ -3,6 +3,8 @@ package modmanager
 
 import (
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/LucasAVasco/falcula/lua/luaclass"
 	"github.com/LucasAVasco/falcula/lua/modules/base"
@@ -18,6 +20,7 @@ type Callbacks struct {
 	OnDeleteManager        func(man *manager.Manager)
 	OnAddService           func(man *manager.Manager, svc *enhanced.EnhancedService)
 	OnServiceStatusChanged func(man *manager.Manager, svc *enhanced.EnhancedService)
+	OnServiceUsageSampled  func(man *manager.Manager, svc *enhanced.EnhancedService) // A resource usage sample was recorded or reset
 }
 
 // Module is a module that provides functions and classes for working with service managers
@@ -25,11 +28,17 @@ type Module struct {
 	base.BaseModule
 
 	callbacks *Callbacks
+	sampler   sampler
 }
 
-func New(callbacks *Callbacks) *Module {
+// New creates the module. The resource usage of the running services is sampled at the provided interval. The sampling is disabled if the
+// interval is zero
+func New(callbacks *Callbacks, sampleInterval time.Duration) *Module {
 	m := Module{
 		callbacks: callbacks,
+		sampler: sampler{
+			interval: sampleInterval,
+		},
 	}
 
 	return &m
@@ -61,6 +70,53 @@ func (m *Module) Loader(L *lua.LState, name string, mod *lua.LTable) error {
 	return nil
 }
 
+// Close resets the log formats and output triggers of the services and stops sampling their resource usage. Can be called multiple times
 func (m *Module) Close() error {
+	m.Config.Runtime.Logger.ResetServiceLogFormats()
+	m.Config.Runtime.Logger.ResetServiceOutputHandlers()
+	m.stopSampler()
 	return nil
 }
+
+// JoinCallbacks returns callbacks that call all the provided callbacks, in order. The nil callbacks (and nil fields) are ignored
+func JoinCallbacks(callbacks ...*Callbacks) *Callbacks {
+	callbacks = slices.DeleteFunc(slices.Clone(callbacks), func(c *Callbacks) bool { return c == nil })
+
+	return &Callbacks{
+		OnNewManager: func(man *manager.Manager) {
+			for _, c := range callbacks {
+				if c.OnNewManager != nil {
+					c.OnNewManager(man)
+				}
+			}
+		},
+		OnDeleteManager: func(man *manager.Manager) {
+			for _, c := range callbacks {
+				if c.OnDeleteManager != nil {
+					c.OnDeleteManager(man)
+				}
+			}
+		},
+		OnAddService: func(man *manager.Manager, svc *enhanced.EnhancedService) {
+			for _, c := range callbacks {
+				if c.OnAddService != nil {
+					c.OnAddService(man, svc)
+				}
+			}
+		},
+		OnServiceStatusChanged: func(man *manager.Manager, svc *enhanced.EnhancedService) {
+			for _, c := range callbacks {
+				if c.OnServiceStatusChanged != nil {
+					c.OnServiceStatusChanged(man, svc)
+				}
+			}
+		},
+		OnServiceUsageSampled: func(man *manager.Manager, svc *enhanced.EnhancedService) {
+			for _, c := range callbacks {
+				if c.OnServiceUsageSampled != nil {
+					c.OnServiceUsageSampled(man, svc)
+				}
+			}
+		},
+	}
+}
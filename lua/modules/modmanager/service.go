@@ -0,0 +1,119 @@
+package modmanager
+
+import (
+	"time"
+
+	"github.com/LucasAVasco/falcula/lua/luaerror"
+	"github.com/LucasAVasco/falcula/service/enhanced"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// newServiceTable creates a Lua table to query and control an enhanced service. Its functions must be called as methods (with ':')
+func (m *Module) newServiceTable(L *lua.LState, svc *enhanced.EnhancedService) *lua.LTable {
+	table := L.NewTable()
+
+	L.SetFuncs(table, map[string]lua.LGFunction{
+		"get_name": func(L *lua.LState) int {
+			L.Push(lua.LString(svc.GetName()))
+			return 1
+		},
+
+		"get_status": func(L *lua.LState) int {
+			L.Push(lua.LString(svc.GetStatus().ToString()))
+			return 1
+		},
+
+		"get_metadata": func(L *lua.LState) int {
+			value, ok := svc.GetMetadata(L.CheckString(2))
+			if !ok {
+				L.Push(lua.LNil)
+				return 1
+			}
+
+			L.Push(lua.LString(value))
+			return 1
+		},
+
+		"get_all_metadata": func(L *lua.LState) int {
+			metadata := L.NewTable()
+			for key, value := range svc.GetAllMetadata() {
+				metadata.RawSetString(key, lua.LString(value))
+			}
+
+			L.Push(metadata)
+			return 1
+		},
+
+		"set_metadata": func(L *lua.LState) int {
+			svc.SetMetadata(L.CheckString(2), L.CheckString(3))
+			return 0
+		},
+
+		"is_ready": func(L *lua.LState) int {
+			L.Push(lua.LBool(svc.IsReady()))
+			return 1
+		},
+
+		"set_ready": func(L *lua.LState) int {
+			svc.SetReady()
+			return 0
+		},
+
+		"wait_ready": func(L *lua.LState) int {
+			timeout := time.Duration(float64(L.OptNumber(2, 0)) * float64(time.Second))
+			ready := false
+			m.wait(L, func() error {
+				ready = svc.WaitReady(timeout)
+				return nil
+			})
+
+			L.Push(lua.LBool(ready))
+			return 1
+		},
+
+		"stats": func(L *lua.LState) int {
+			stats := L.NewTable()
+
+			// Last resource usage sample
+			if usage, ok := svc.GetUsage(); ok {
+				stats.RawSetString("cpu", lua.LNumber(usage.CPUPercent))
+				stats.RawSetString("memory", lua.LNumber(usage.RSS))
+				stats.RawSetString("fds", lua.LNumber(usage.FDs))
+				stats.RawSetString("threads", lua.LNumber(usage.Threads))
+			}
+
+			serviceStats := svc.GetStats()
+			stats.RawSetString("restarts", lua.LNumber(serviceStats.Restarts))
+			stats.RawSetString("uptime", lua.LNumber(svc.GetUptime().Seconds()))
+			if serviceStats.LastExitInfo != nil {
+				stats.RawSetString("exit_code", lua.LNumber(serviceStats.LastExitInfo.Code))
+			}
+
+			L.Push(stats)
+			return 1
+		},
+
+		"restart": func(L *lua.LState) int {
+			force := L.OptBool(2, false)
+			_, err := svc.Restart(force)
+			if err != nil {
+				return luaerror.Push(L, 0, err)
+			}
+
+			return 0
+		},
+
+		"stop": func(L *lua.LState) int {
+			force := L.OptBool(2, false)
+			_, err := svc.AbortPrepareOrStop(force)
+			if err != nil {
+				return luaerror.Push(L, 0, err)
+			}
+
+			return 0
+		},
+	})
+
+	return table
+}
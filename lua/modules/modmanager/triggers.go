@@ -0,0 +1,110 @@
+package modmanager
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/LucasAVasco/falcula/logsink"
+	"github.com/LucasAVasco/falcula/lua/luapattern"
+	"github.com/LucasAVasco/falcula/lua/luaruntime/logger"
+	"github.com/LucasAVasco/falcula/service/enhanced"
+	"github.com/LucasAVasco/falcula/service/iface"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// validateOutputTriggers checks the patterns and actions of the output triggers of a service
+func validateOutputTriggers(triggers []*iface.OutputTrigger) error {
+	for _, trigger := range triggers {
+		err := luapattern.Validate(trigger.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid output trigger pattern '%s': %w", trigger.Pattern, err)
+		}
+
+		switch action := trigger.Action.(type) {
+		case nil, *lua.LFunction:
+		case string:
+			if action != iface.OutputActionReady && action != iface.OutputActionRestart && action != iface.OutputActionStop {
+				return fmt.Errorf("invalid action '%s' of output trigger '%s', must be '%s', '%s', '%s' or a function", action,
+					trigger.Pattern, iface.OutputActionReady, iface.OutputActionRestart, iface.OutputActionStop)
+			}
+		default:
+			return fmt.Errorf("invalid action type '%T' of output trigger '%s'", action, trigger.Pattern)
+		}
+	}
+
+	return nil
+}
+
+// newOutputHandler creates the logger handler that evaluates the output triggers of a service. The Lua functions are queued to run in the
+// goroutine of the script (see `luaruntime.Runtime.QueueCallback`)
+func (m *Module) newOutputHandler(svc *enhanced.EnhancedService, triggers []*iface.OutputTrigger) logger.OutputHandler {
+	var stopping atomic.Bool // Avoids restarting or stopping the service again while it is already being restarted or stopped
+
+	return func(entry *logsink.Entry) {
+		for _, trigger := range triggers {
+			captures, matched, err := luapattern.Find(trigger.Pattern, entry.Message)
+			if err != nil || !matched {
+				continue
+			}
+
+			// The metadata is set synchronously, so it is already available to the next lines
+			if trigger.Metadata != "" {
+				value := entry.Message
+				if len(captures) > 0 {
+					value = captures[0]
+				}
+				svc.SetMetadata(trigger.Metadata, value)
+			}
+
+			switch action := trigger.Action.(type) {
+			case string:
+				if action == iface.OutputActionReady {
+					go svc.SetReady()
+					continue
+				}
+
+				if !stopping.CompareAndSwap(false, true) {
+					continue
+				}
+
+				go func() {
+					defer stopping.Store(false)
+
+					var err error
+					if action == iface.OutputActionRestart {
+						_, err = svc.Restart(false)
+					} else {
+						_, err = svc.AbortPrepareOrStop(false)
+					}
+
+					if err != nil {
+						m.Config.Runtime.Logger.LogError(fmt.Errorf("error running action '%s' of output trigger '%s' of service '%s': %w",
+							action, trigger.Pattern, svc.GetName(), err))
+					}
+				}()
+
+			case *lua.LFunction:
+				m.Config.Runtime.QueueCallback(func(L *lua.LState) {
+					args := []lua.LValue{m.newServiceTable(L, svc)}
+					for _, capture := range captures {
+						args = append(args, lua.LString(capture))
+					}
+
+					// A separate Lua thread does not change the stack of the script, that can be waiting in a blocking function. An error of
+					// the function would close the upvalues of the script if it was called in the same thread
+					thread, cancel := L.NewThread()
+					if cancel != nil {
+						defer cancel()
+					}
+
+					err := thread.CallByParam(lua.P{Fn: action, NRet: 0, Protect: true}, args...)
+					if err != nil {
+						m.Config.Runtime.Logger.LogError(fmt.Errorf("error calling action of output trigger '%s' of service '%s': %w",
+							trigger.Pattern, svc.GetName(), err))
+					}
+				})
+			}
+		}
+	}
+}
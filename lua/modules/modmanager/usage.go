@@ -0,0 +1,269 @@
+package modmanager
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/LucasAVasco/falcula/procstat"
+	"github.com/LucasAVasco/falcula/service/enhanced"
+	"github.com/LucasAVasco/falcula/service/iface"
+	"github.com/LucasAVasco/falcula/service/manager"
+	"github.com/LucasAVasco/falcula/units"
+)
+
+// sampler samples the resource usage of the services added by the module. The sampling starts when the first service is added
+type sampler struct {
+	interval time.Duration // Disabled if zero
+
+	mutex    sync.Mutex
+	services []*sampledService
+	stop     chan struct{} // Closed to stop the sampling. Nil if not sampling
+	done     chan struct{} // Closed when the sampling stops
+}
+
+// sampledService is a service whose resource usage is sampled. Its fields are only used by the sampling routine
+type sampledService struct {
+	manager    *manager.Manager
+	service    *enhanced.EnhancedService
+	thresholds []*threshold
+	restarting atomic.Bool // Avoids restarting the service again while it is already being restarted
+
+	last     *procstat.Stat // Last sample. Nil if the service was not running
+	lastTime time.Time
+}
+
+// threshold is a validated resource threshold
+type threshold struct {
+	*iface.ResourceThreshold
+	max      float64
+	exceeded int // Number of consecutive samples above the maximum
+}
+
+// newThresholds validates the resource thresholds of a service and parses their maximum values
+func newThresholds(resourceThresholds []*iface.ResourceThreshold) ([]*threshold, error) {
+	thresholds := make([]*threshold, 0, len(resourceThresholds))
+
+	for _, resourceThreshold := range resourceThresholds {
+		t := threshold{ResourceThreshold: resourceThreshold}
+
+		switch t.Resource {
+		case iface.ResourceCPU, iface.ResourceMemory, iface.ResourceFDs, iface.ResourceThreads:
+		default:
+			return nil, fmt.Errorf("invalid resource '%s', must be '%s', '%s', '%s' or '%s'", t.Resource, iface.ResourceCPU,
+				iface.ResourceMemory, iface.ResourceFDs, iface.ResourceThreads)
+		}
+
+		switch t.Action {
+		case "":
+			t.Action = iface.ThresholdActionWarn
+		case iface.ThresholdActionWarn, iface.ThresholdActionRestart:
+		default:
+			return nil, fmt.Errorf("invalid action '%s' of the '%s' threshold, must be '%s' or '%s'", t.Action, t.Resource,
+				iface.ThresholdActionWarn, iface.ThresholdActionRestart)
+		}
+
+		if t.Samples <= 0 {
+			t.Samples = 1
+		}
+
+		switch value := t.Max.(type) {
+		case float64:
+			t.max = value
+		case string:
+			var err error
+			if t.Resource == iface.ResourceMemory {
+				var size int64
+				size, err = units.ParseBytes(value)
+				t.max = float64(size)
+			} else {
+				t.max, err = strconv.ParseFloat(value, 64)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid maximum '%s' of the '%s' threshold: %w", value, t.Resource, err)
+			}
+		default:
+			return nil, fmt.Errorf("invalid maximum of type '%T' of the '%s' threshold, must be a number or a string", value, t.Resource)
+		}
+
+		thresholds = append(thresholds, &t)
+	}
+
+	return thresholds, nil
+}
+
+// getValue returns the value of the threshold resource in a sample
+func (t *threshold) getValue(usage *enhanced.Usage) float64 {
+	switch t.Resource {
+	case iface.ResourceCPU:
+		return usage.CPUPercent
+	case iface.ResourceMemory:
+		return float64(usage.RSS)
+	case iface.ResourceFDs:
+		return float64(usage.FDs)
+	default:
+		return float64(usage.Threads)
+	}
+}
+
+// formatValue formats a value of the threshold resource
+func (t *threshold) formatValue(value float64) string {
+	switch t.Resource {
+	case iface.ResourceCPU:
+		return fmt.Sprintf("%.1f%%", value)
+	case iface.ResourceMemory:
+		return units.FormatBytes(int64(value))
+	default:
+		return strconv.FormatFloat(value, 'f', 0, 64)
+	}
+}
+
+// sampleService starts sampling the resource usage of a service
+func (m *Module) sampleService(man *manager.Manager, svc *enhanced.EnhancedService, thresholds []*threshold) {
+	if m.sampler.interval <= 0 {
+		return
+	}
+
+	m.sampler.mutex.Lock()
+	defer m.sampler.mutex.Unlock()
+
+	m.sampler.services = append(m.sampler.services, &sampledService{
+		manager:    man,
+		service:    svc,
+		thresholds: thresholds,
+	})
+
+	if m.sampler.stop == nil {
+		m.sampler.stop = make(chan struct{})
+		m.sampler.done = make(chan struct{})
+		go m.runSampler(m.sampler.stop, m.sampler.done)
+	}
+}
+
+// stopSampler stops sampling and forgets the sampled services. Can be called multiple times
+func (m *Module) stopSampler() {
+	m.sampler.mutex.Lock()
+	stop, done := m.sampler.stop, m.sampler.done
+	m.sampler.stop, m.sampler.done = nil, nil
+	m.sampler.services = nil
+	m.sampler.mutex.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// runSampler samples the resource usage of the services at each interval until the stop channel is closed
+func (m *Module) runSampler(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(m.sampler.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		err := m.sampleUsage()
+		if err != nil {
+			m.Config.Runtime.Logger.LogError(fmt.Errorf("error sampling the resource usage of the services, sampling disabled: %w", err))
+			return
+		}
+	}
+}
+
+// sampleUsage records a resource usage sample of each running service and checks their thresholds
+func (m *Module) sampleUsage() error {
+	m.sampler.mutex.Lock()
+	services := slices.Clone(m.sampler.services)
+	m.sampler.mutex.Unlock()
+
+	var snapshot *procstat.Snapshot // Only read if a service is running
+	for _, sampled := range services {
+		pids := sampled.service.GetPids()
+		if len(pids) == 0 {
+			if sampled.last != nil {
+				sampled.last = nil
+				sampled.service.ResetUsage()
+				m.callbacks.OnServiceUsageSampled(sampled.manager, sampled.service)
+			}
+			continue
+		}
+
+		if snapshot == nil {
+			var err error
+			snapshot, err = procstat.NewSnapshot()
+			if err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		stat := snapshot.ReadTree(pids...)
+		usage := enhanced.Usage{
+			Time:    now,
+			RSS:     stat.RSS,
+			FDs:     stat.FDs,
+			Threads: stat.Threads,
+		}
+
+		// The CPU usage is the CPU time since the last sample. The CPU time decreases if a process of the service ended
+		if sampled.last != nil && stat.CPUTime >= sampled.last.CPUTime {
+			usage.CPUPercent = float64(stat.CPUTime-sampled.last.CPUTime) / float64(now.Sub(sampled.lastTime)) * 100
+		}
+		sampled.last, sampled.lastTime = stat, now
+
+		sampled.service.RecordUsage(usage)
+		m.callbacks.OnServiceUsageSampled(sampled.manager, sampled.service)
+		m.checkThresholds(sampled, &usage)
+	}
+
+	return nil
+}
+
+// checkThresholds runs the actions of the thresholds exceeded by a sample. An action runs once each time its threshold is exceeded for the
+// required number of consecutive samples
+func (m *Module) checkThresholds(sampled *sampledService, usage *enhanced.Usage) {
+	for _, t := range sampled.thresholds {
+		value := t.getValue(usage)
+		if value <= t.max {
+			t.exceeded = 0
+			continue
+		}
+
+		t.exceeded++
+		if t.exceeded != t.Samples {
+			continue
+		}
+
+		message := fmt.Sprintf("the %s usage of the service '%s' (%s) is above the maximum (%s)", t.Resource, sampled.service.GetName(),
+			t.formatValue(value), t.formatValue(t.max))
+
+		if t.Action == iface.ThresholdActionWarn {
+			m.Config.Runtime.Logger.LogDebug("Warning: " + message + "\n")
+			continue
+		}
+
+		if !sampled.restarting.CompareAndSwap(false, true) {
+			continue
+		}
+
+		m.Config.Runtime.Logger.LogDebug("Restarting: " + message + "\n")
+		go func() {
+			defer sampled.restarting.Store(false)
+
+			_, err := sampled.service.Restart(false)
+			if err != nil {
+				m.Config.Runtime.Logger.LogError(fmt.Errorf("error restarting service '%s' after exceeding the %s threshold: %w",
+					sampled.service.GetName(), t.Resource, err))
+			}
+		}()
+	}
+}
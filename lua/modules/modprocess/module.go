@@ -11,6 +11,7 @@ import (
 	"github.com/LucasAVasco/falcula/lua/maplua"
 	"github.com/LucasAVasco/falcula/lua/modules/base"
 	"github.com/LucasAVasco/falcula/provider/process"
+	"github.com/LucasAVasco/falcula/units"
 
 	lua "github.com/yuin/gopher-lua"
 )
@@ -44,6 +45,14 @@ func getProcessCommandFromLuaValue(value lua.LValue) (*process.Command, error) {
 			command.Dir = dir.(lua.LString).String()
 		}
 
+		if limits, ok := value.RawGetString("limits").(*lua.LTable); ok {
+			var err error
+			command.Limits, err = getLimitsFromLuaTable(limits)
+			if err != nil {
+				return nil, fmt.Errorf("error getting resource limits: %w", err)
+			}
+		}
+
 	default:
 		return nil, fmt.Errorf("invalid command type: %T", value)
 	}
@@ -51,6 +60,45 @@ func getProcessCommandFromLuaValue(value lua.LValue) (*process.Command, error) {
 	return &command, nil
 }
 
+// luaLimits are the resource limits of a command in Lua
+type luaLimits struct {
+	Memory     any    `lua:"memory"` // Number of bytes or size with unit (e.g. '2G')
+	CPUSeconds uint64 `lua:"cpu_seconds"`
+	NoFile     uint64 `lua:"nofile"`
+	NProc      uint64 `lua:"nproc"`
+}
+
+// getLimitsFromLuaTable gets the resource limits of a command from a Lua table
+func getLimitsFromLuaTable(table *lua.LTable) (*process.Limits, error) {
+	config := luaLimits{}
+	err := maplua.Unmarshal(table, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := process.Limits{
+		CPUSeconds: config.CPUSeconds,
+		NoFile:     config.NoFile,
+		NProc:      config.NProc,
+	}
+
+	switch memory := config.Memory.(type) {
+	case nil:
+	case float64:
+		limits.Memory = uint64(memory)
+	case string:
+		size, err := units.ParseBytes(memory)
+		if err != nil {
+			return nil, fmt.Errorf("invalid memory limit: %w", err)
+		}
+		limits.Memory = uint64(size)
+	default:
+		return nil, fmt.Errorf("invalid memory limit of type '%T', must be a number or a string", memory)
+	}
+
+	return &limits, nil
+}
+
 func (l *Loader) Loader(L *lua.LState, name string, mod *lua.LTable) error {
 	info := luaclass.Info{
 		Name: "Provider",
@@ -70,6 +118,7 @@ func (l *Loader) Loader(L *lua.LState, name string, mod *lua.LTable) error {
 			config := process.ProviderConfig{
 				Multiplexer: l.Config.Runtime.Logger.GetServicesMultiplexer(),
 				Name:        name,
+				Recorder:    l.Config.Runtime.GetProcessRecorder(),
 			}
 
 			err = maplua.Unmarshal(L.OptTable(5, L.NewTable()), &config.Opts)
@@ -0,0 +1,57 @@
+// Package modsecret is a module that provides functions to register secrets that are masked in all logs
+package modsecret
+
+import (
+	"os"
+
+	"github.com/LucasAVasco/falcula/lua/luaerror"
+	"github.com/LucasAVasco/falcula/lua/luatable"
+	"github.com/LucasAVasco/falcula/lua/modules/base"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Module is a module that provides functions to register secrets that are masked in all logs. The secrets are kept until Falcula exits
+// (they are not removed when the script is re-run)
+type Module struct {
+	base.BaseModule
+}
+
+func New() *Module {
+	return &Module{}
+}
+
+func (m *Module) Loader(L *lua.LState, name string, mod *lua.LTable) error {
+	L.SetFuncs(mod, map[string]lua.LGFunction{
+		"register": func(L *lua.LState) int {
+			m.Config.Runtime.Logger.GetRedactor().AddValue(L.CheckString(1))
+			return 0
+		},
+
+		"register_env": func(L *lua.LState) int {
+			globs := luatable.GetStringsFromLuaTableThatKeyIsNumber(L.CheckTable(1))
+			err := m.Config.Runtime.Logger.GetRedactor().AddEnv(globs, os.Environ())
+			if err != nil {
+				return luaerror.Push(L, 0, err)
+			}
+
+			return 0
+		},
+
+		"register_pattern": func(L *lua.LState) int {
+			err := m.Config.Runtime.Logger.GetRedactor().AddPattern(L.CheckString(1))
+			if err != nil {
+				return luaerror.Push(L, 0, err)
+			}
+
+			return 0
+		},
+
+		"redact": func(L *lua.LState) int {
+			L.Push(lua.LString(m.Config.Runtime.Logger.GetRedactor().Redact(L.CheckString(1))))
+			return 1
+		},
+	})
+
+	return nil
+}
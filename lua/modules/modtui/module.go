@@ -101,6 +101,13 @@ func TuiIsVisible() bool {
 	return serviceTui.IsVisible()
 }
 
+// HidePersistentTui hides the persistent text user interface if it exists
+func HidePersistentTui() {
+	if serviceTui != nil {
+		serviceTui.Hide()
+	}
+}
+
 // ClosePersistentTui closes (deletes) the persistent text user interface
 func ClosePersistentTui() {
 	if serviceTui != nil {
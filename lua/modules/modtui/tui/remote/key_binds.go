@@ -0,0 +1,67 @@
+package remote
+
+import (
+	"fmt"
+
+	"github.com/LucasAVasco/falcula/control"
+	"github.com/LucasAVasco/falcula/lua/modules/modtui/tui/keybinds"
+)
+
+// setKeyBinds sets the key binds of the main page
+func (r *Remote) setKeyBinds() {
+	r.keyBindsHandler = keybinds.NewHandler("Main page")
+	r.keyBindsHandler.AddChildHandler(r.sidebar.keyBindsHandler)
+
+	r.keyBindsHandler.AddKeyBinds([]*keybinds.KeyBind{
+		// Exit
+		{
+			Rune: 'q',
+			Desc: "Detach (the session keeps running)",
+			Bind: func() { r.app.Stop() },
+		},
+		{
+			Rune:  'Q',
+			Desc:  "Shut down the session and exit",
+			Async: true,
+			Bind: func() {
+				err := r.client.Call(control.MethodShutdown, nil, nil)
+				if err != nil {
+					r.debugLogs.Append(fmt.Errorf("error shutting down session: %w", err), "\n")
+				}
+			},
+		},
+		// Scroll the logs
+		{
+			Rune: 'g',
+			Desc: "Scroll to the beginning of the logs",
+			Bind: func() {
+				r.serviceLogs.ScrollToBeginning()
+				r.debugLogs.ScrollToBeginning()
+			},
+		},
+		{
+			Rune: 'G',
+			Desc: "Scroll to the end of the logs",
+			Bind: func() {
+				r.serviceLogs.ScrollToEnd()
+				r.debugLogs.ScrollToEnd()
+			},
+		},
+		// Other pages
+		{
+			Rune: 'a',
+			Desc: "Open arguments page",
+			Bind: r.focusArgumentsPage,
+		},
+		// Help menu
+		{
+			Rune: '?',
+			Desc: "Open the help menu",
+			Bind: func() {
+				r.help.Open(r.keyBindsHandler, r.focusMainPage)
+			},
+		},
+	})
+
+	r.mainFlex.SetInputCapture(r.keyBindsHandler.GetInputCaptureFunction())
+}
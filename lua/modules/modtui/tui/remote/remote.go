@@ -0,0 +1,271 @@
+// Package remote implements a text user interface attached to a running session through its control socket. The session can run in another
+// process (e.g. a detached session)
+package remote
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/LucasAVasco/falcula/control"
+	"github.com/LucasAVasco/falcula/logsink"
+	"github.com/LucasAVasco/falcula/lua/modules/modtui/tui/app"
+	"github.com/LucasAVasco/falcula/lua/modules/modtui/tui/argsview"
+	"github.com/LucasAVasco/falcula/lua/modules/modtui/tui/help"
+	"github.com/LucasAVasco/falcula/lua/modules/modtui/tui/keybinds"
+	"github.com/LucasAVasco/falcula/lua/modules/modtui/tui/logpreview"
+
+	"github.com/rivo/tview"
+)
+
+// ErrSessionEnded is returned by `Attach` when the session ends while the user interface is open
+var ErrSessionEnded = errors.New("the session ended")
+
+// pollInterval is the interval between the updates of the services and the arguments
+const pollInterval = 500 * time.Millisecond
+
+// Remote is the text user interface of a remote session
+type Remote struct {
+	socketPath string
+	client     *control.Client // Used by the key binds and to update the user interface
+	logsClient *control.Client // Receives the logs
+	info       control.SessionInfo
+
+	done         chan struct{}
+	waitGroup    sync.WaitGroup
+	sessionEnded atomic.Bool
+
+	// User interface
+
+	app             *app.App
+	pages           *tview.Pages
+	mainFlex        *tview.Flex
+	keyBindsHandler *keybinds.Handler
+	sidebar         *serviceTree
+	serviceLogs     *logpreview.Preview
+	debugLogs       *logpreview.Preview
+	argsView        *argsview.ArgsView
+	help            *help.HelpWidget
+
+	// Only accessed by the user interface goroutine
+	availableArgs [][]string
+	currentArgs   []string
+}
+
+// Attach opens the text user interface of the session that listens on the socket. Blocks until the user closes the user interface (the
+// session keeps running) or the session ends (returns `ErrSessionEnded`)
+func Attach(socketPath string) error {
+	r := Remote{
+		socketPath: socketPath,
+		done:       make(chan struct{}),
+	}
+
+	var err error
+	r.client, err = control.Dial(socketPath)
+	if err != nil {
+		return err
+	}
+	defer r.client.Close()
+
+	err = r.client.Call(control.MethodInfo, nil, &r.info)
+	if err != nil {
+		return fmt.Errorf("error getting session information: %w", err)
+	}
+
+	r.logsClient, err = control.Dial(socketPath)
+	if err != nil {
+		return err
+	}
+	defer r.logsClient.Close()
+
+	r.newApp()
+
+	r.waitGroup.Go(r.receiveLogs)
+	r.waitGroup.Go(r.pollSession)
+
+	err = r.app.Run()
+
+	// Stops receiving the logs and polling the session
+	close(r.done)
+	r.logsClient.Close()
+	r.waitGroup.Wait()
+
+	if err != nil {
+		return fmt.Errorf("error running 'tview' application: %w", err)
+	}
+
+	if r.sessionEnded.Load() {
+		return ErrSessionEnded
+	}
+
+	return nil
+}
+
+// newApp creates the application and its pages
+func (r *Remote) newApp() {
+	r.app = app.Extend(tview.NewApplication())
+
+	r.pages = tview.NewPages()
+	r.app.SetRoot(r.pages, true).
+		EnableMouse(true)
+
+	// Help page
+	r.help = help.New(r.app)
+	r.help.OnOpen = func() {
+		r.pages.ShowPage("help")
+	}
+	r.help.OnExit = func() {
+		r.pages.HidePage("help")
+	}
+
+	// Main page
+	r.mainFlex = tview.NewFlex().
+		SetDirection(tview.FlexColumn)
+
+	r.sidebar = newServiceTree(r.app, r.client, r.info.LogFile)
+	r.sidebar.OnError = func(err error) {
+		r.debugLogs.Append(err, "\n")
+	}
+	r.sidebar.tree.SetTitle("Session " + r.info.Session)
+	r.mainFlex.AddItem(r.sidebar.tree, 0, 1, false)
+
+	logsFlex := tview.NewFlex().
+		SetDirection(tview.FlexRow)
+	r.mainFlex.AddItem(logsFlex, 0, 3, false)
+
+	r.serviceLogs = logpreview.New(r.app, "Services logs", 100)
+	logsFlex.AddItem(r.serviceLogs.GetPrimitive(), 0, 2, false)
+
+	r.debugLogs = logpreview.New(r.app, "Debug logs", 100)
+	logsFlex.AddItem(r.debugLogs.GetPrimitive(), 0, 1, false)
+
+	r.setKeyBinds()
+	r.pages.AddPage("main", r.mainFlex, true, false)
+
+	// Arguments page
+	r.argsView = argsview.New(r.app, r.help)
+	r.argsView.OnExit = r.focusMainPage
+	r.argsView.OnSelected = func(args []string) {
+		r.focusMainPage()
+
+		err := r.client.Call(control.MethodRerun, &control.RerunParams{Args: args}, nil)
+		if err != nil {
+			r.debugLogs.Append(fmt.Errorf("error re-running script: %w", err), "\n")
+		}
+	}
+	r.pages.AddPage("arguments", r.argsView.GetPrimitive(), true, false)
+
+	// NOTE(LucasAVasco): must be the last page in the list to be on top
+	r.pages.AddPage("help", r.help.GetPrimitive(), true, false)
+
+	r.focusMainPage()
+}
+
+func (r *Remote) focusMainPage() {
+	r.pages.SwitchToPage("main")
+	r.app.SetFocus(r.sidebar.tree)
+}
+
+func (r *Remote) focusArgumentsPage() {
+	r.pages.SwitchToPage("arguments")
+	r.argsView.FocusAvailableArgs()
+}
+
+// receiveLogs shows the logs of the session (the buffered ones and the new ones). Closes the user interface when the session ends
+func (r *Remote) receiveLogs() {
+	params := control.LogsParams{
+		Lines:  -1,
+		Follow: true,
+		Debug:  true,
+	}
+
+	err := r.logsClient.Stream(control.MethodLogs, &params, nil, func(method string, data json.RawMessage) error {
+		entry := control.LogEntry{}
+		err := json.Unmarshal(data, &entry)
+		if err != nil {
+			return fmt.Errorf("error decoding log entry: %w", err)
+		}
+
+		line := entry.Line
+		if line == "" {
+			line = logsink.FormatSyslog(entry.ToSinkEntry())
+		}
+
+		switch method {
+		case control.NotificationLog:
+			r.serviceLogs.Append(line)
+		case control.NotificationDebug:
+			r.debugLogs.Append(line)
+		}
+
+		return nil
+	})
+
+	select {
+	case <-r.done: // Closed by the user
+		return
+	default:
+	}
+
+	if err != nil {
+		r.debugLogs.Append(fmt.Errorf("error receiving logs: %w", err), "\n")
+	}
+
+	r.sessionEnded.Store(true)
+	r.app.Stop()
+}
+
+// pollSession updates the services and the arguments shown in the user interface until it is closed
+func (r *Remote) pollSession() {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		managers := []*control.ManagerInfo{}
+		err := r.client.Call(control.MethodList, nil, &managers)
+		if err != nil {
+			r.debugLogs.Append(fmt.Errorf("error listing services: %w", err), "\n")
+			return
+		}
+
+		args := control.ArgsInfo{}
+		err = r.client.Call(control.MethodArgs, nil, &args)
+		if err != nil {
+			r.debugLogs.Append(fmt.Errorf("error getting arguments: %w", err), "\n")
+			return
+		}
+
+		select {
+		case <-r.done:
+			return
+		default:
+		}
+
+		r.sidebar.update(managers)
+		r.updateArgs(&args)
+		r.app.Draw()
+
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// updateArgs updates the arguments view if the arguments changed
+func (r *Remote) updateArgs(args *control.ArgsInfo) {
+	if !slices.Equal(r.currentArgs, args.Current) {
+		r.currentArgs = args.Current
+		r.argsView.SetCurrentArgs(args.Current)
+	}
+
+	if !slices.EqualFunc(r.availableArgs, args.Available, slices.Equal) {
+		r.availableArgs = args.Available
+		r.argsView.SetAvailableArgs(args.Available)
+	}
+}
@@ -0,0 +1,283 @@
+package remote
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/LucasAVasco/falcula/control"
+	"github.com/LucasAVasco/falcula/lua/modules/modtui/tui/app"
+	"github.com/LucasAVasco/falcula/lua/modules/modtui/tui/keybinds"
+	"github.com/LucasAVasco/falcula/lua/modules/modtui/tui/sidebar"
+	"github.com/LucasAVasco/falcula/lua/modules/modtui/tui/usageview"
+	"github.com/LucasAVasco/falcula/service/status"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// nodeReference is the reference of a node of the side bar. The service is empty in manager nodes
+type nodeReference struct {
+	manager string
+	service string
+}
+
+// getTarget returns the target of the control API that selects the services of the node
+func (n *nodeReference) getTarget() string {
+	if n.service == "" {
+		return n.manager
+	}
+
+	return n.manager + "/" + n.service
+}
+
+// serviceTree is the side bar with the managers and services of the remote session
+type serviceTree struct {
+	app             *app.App
+	client          *control.Client
+	keyBindsHandler *keybinds.Handler
+	logFilePath     string
+	managers        []*control.ManagerInfo // Last update
+
+	// Widgets
+
+	tree *tview.TreeView
+	root *tview.TreeNode
+
+	// Callbacks
+	OnError func(err error)
+}
+
+func newServiceTree(app *app.App, client *control.Client, logFilePath string) *serviceTree {
+	s := serviceTree{
+		app:         app,
+		client:      client,
+		logFilePath: logFilePath,
+
+		// Callbacks
+		OnError: func(err error) {},
+	}
+
+	s.tree = tview.NewTreeView()
+	s.root = tview.NewTreeNode("Service managers")
+	s.tree.SetRoot(s.root)
+	s.tree.SetCurrentNode(s.root)
+	s.tree.SetBorder(true)
+
+	s.setKeyBinds()
+
+	return &s
+}
+
+// generateServiceText gets the text to show in the service node
+func generateServiceText(svc *control.ServiceInfo) string {
+	if svc.Status == status.Running.ToString() && svc.Ready {
+		return svc.Name + " (" + svc.Status + ", ready)"
+	}
+
+	return svc.Name + " (" + svc.Status + ")"
+}
+
+// getChild returns the child of a node with the reference. Returns nil if it is not found
+func getChild(node *tview.TreeNode, reference nodeReference) *tview.TreeNode {
+	children := node.GetChildren()
+	index := slices.IndexFunc(children, func(child *tview.TreeNode) bool {
+		return child.GetReference() == reference
+	})
+
+	if index == -1 {
+		return nil
+	}
+
+	return children[index]
+}
+
+// removeMissingChildren removes the children whose reference is not in the list
+func removeMissingChildren(node *tview.TreeNode, references []nodeReference) {
+	for _, child := range node.GetChildren() {
+		if !slices.Contains(references, child.GetReference().(nodeReference)) {
+			node.RemoveChild(child)
+		}
+	}
+}
+
+// update updates the tree with the managers and services of the session
+func (s *serviceTree) update(managers []*control.ManagerInfo) {
+	s.app.Lock()
+	defer s.app.Unlock()
+
+	s.managers = managers
+
+	managerReferences := []nodeReference{}
+	for _, man := range managers {
+		managerReference := nodeReference{manager: man.Name}
+		managerReferences = append(managerReferences, managerReference)
+
+		managerNode := getChild(s.root, managerReference)
+		if managerNode == nil {
+			managerNode = tview.NewTreeNode(man.Name).SetReference(managerReference).SetSelectable(true)
+			s.root.AddChild(managerNode)
+		}
+
+		serviceReferences := []nodeReference{}
+		for _, svc := range man.Services {
+			serviceReference := nodeReference{manager: man.Name, service: svc.Name}
+			serviceReferences = append(serviceReferences, serviceReference)
+
+			serviceNode := getChild(managerNode, serviceReference)
+			if serviceNode == nil {
+				serviceNode = tview.NewTreeNode("").SetReference(serviceReference).SetSelectable(true)
+				managerNode.AddChild(serviceNode)
+			}
+			serviceNode.SetText(generateServiceText(svc))
+			usageview.UpdateNode(serviceNode, svc.Usage) // The session only sends the usage of the running services
+		}
+
+		removeMissingChildren(managerNode, serviceReferences)
+	}
+
+	removeMissingChildren(s.root, managerReferences)
+
+	// The current node may have been removed
+	found := false
+	s.root.Walk(func(node, parent *tview.TreeNode) bool {
+		found = found || node == s.tree.GetCurrentNode()
+		return !found
+	})
+	if !found {
+		s.tree.SetCurrentNode(s.root)
+	}
+}
+
+// getCurrentReference returns the reference of the current node. Returns nil if the current node is the root
+func (s *serviceTree) getCurrentReference() *nodeReference {
+	node := s.tree.GetCurrentNode()
+	if node == nil {
+		return nil
+	}
+
+	reference, ok := node.GetReference().(nodeReference)
+	if !ok {
+		return nil
+	}
+
+	return &reference
+}
+
+// runAction runs an action of the control API on the services of the current node (all services if the current node is the root)
+func (s *serviceTree) runAction(method string, force bool) {
+	params := control.TargetParams{Force: force}
+	if reference := s.getCurrentReference(); reference != nil {
+		params.Target = reference.getTarget()
+	}
+
+	err := s.client.Call(method, &params, nil)
+	if err != nil {
+		s.OnError(fmt.Errorf("error running '%s': %w", method, err))
+	}
+}
+
+// getLnavFilter returns the Lnav filter that selects the logs of the current node
+func (s *serviceTree) getLnavFilter() string {
+	reference := s.getCurrentReference()
+	if reference == nil {
+		return ""
+	}
+
+	if reference.service != "" {
+		return fmt.Sprintf(":log_hostname == '%s'", reference.service)
+	}
+
+	filter := ""
+	for _, man := range s.managers {
+		if man.Name != reference.manager {
+			continue
+		}
+
+		for i, svc := range man.Services {
+			if i > 0 {
+				filter += " or "
+			}
+			filter += fmt.Sprintf(":log_hostname == '%s'", svc.Name)
+		}
+	}
+
+	return filter
+}
+
+// setKeyBinds sets the key binds for the side bar
+func (s *serviceTree) setKeyBinds() {
+	lnavKeyBind := func() {
+		s.app.Suspend(func() {
+			err := sidebar.OpenLogFileInLnav(s.logFilePath, s.getLnavFilter())
+			if err != nil {
+				s.OnError(err)
+			}
+		})
+	}
+
+	s.keyBindsHandler = keybinds.NewHandler("Sidebar")
+	s.keyBindsHandler.AddKeyBinds([]*keybinds.KeyBind{
+		// Open current node
+		{
+			Key:  tcell.KeyEnter,
+			Desc: "Open current node in Lnav",
+			Bind: lnavKeyBind,
+		},
+		{
+			Rune: 'l',
+			Desc: "Open current node in Less",
+			Bind: func() {
+				s.app.Suspend(func() {
+					err := sidebar.OpenLogFileInLess(s.logFilePath)
+					if err != nil {
+						s.OnError(err)
+					}
+				})
+			},
+		},
+		{
+			Rune: 'L',
+			Desc: "Open current node in Lnav",
+			Bind: lnavKeyBind,
+		},
+		// Actions
+		{
+			Rune:  'r',
+			Desc:  "Restart current node",
+			Async: true,
+			Bind:  func() { s.runAction(control.MethodRestart, false) },
+		},
+		{
+			Rune:  'R',
+			Desc:  "Restart current node (force)",
+			Async: true,
+			Bind:  func() { s.runAction(control.MethodRestart, true) },
+		},
+		{
+			Rune:  's',
+			Desc:  "Stop or abort prepare current node",
+			Async: true,
+			Bind:  func() { s.runAction(control.MethodStop, false) },
+		},
+		{
+			Rune:  'S',
+			Desc:  "Stop or abort prepare current node (force)",
+			Async: true,
+			Bind:  func() { s.runAction(control.MethodStop, true) },
+		},
+		{
+			Rune:  'd',
+			Desc:  "Disable service (must be enabled to be started)",
+			Async: true,
+			Bind:  func() { s.runAction(control.MethodDisable, false) },
+		},
+		{
+			Rune:  'e',
+			Desc:  "Enable service",
+			Async: true,
+			Bind:  func() { s.runAction(control.MethodEnable, false) },
+		},
+	})
+
+	s.tree.SetInputCapture(s.keyBindsHandler.GetInputCaptureFunction())
+}
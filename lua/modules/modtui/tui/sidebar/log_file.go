@@ -8,7 +8,17 @@ import (
 
 // openLogFileInLess opens the log file in `less`
 func (s *Sidebar) openLogFileInLess() error {
-	cmd := exec.Command("less", s.logFilePath)
+	return OpenLogFileInLess(s.logFilePath)
+}
+
+// openLogFileInLnav opens the log file in `Lnav`. The filter is a Lnav expression used in the `:filter-expr` command. It is optional.
+func (s *Sidebar) openLogFileInLnav(filter string) error {
+	return OpenLogFileInLnav(s.logFilePath, filter)
+}
+
+// OpenLogFileInLess opens a log file in `less`
+func OpenLogFileInLess(logFilePath string) error {
+	cmd := exec.Command("less", logFilePath)
 
 	// Redirection
 	cmd.Stdin = os.Stdin
@@ -24,9 +34,9 @@ func (s *Sidebar) openLogFileInLess() error {
 	return nil
 }
 
-// openLogFileInLnav opens the log file in `Lnav`. The filter is a Lnav expression used in the `:filter-expr` command. It is optional.
-func (s *Sidebar) openLogFileInLnav(filter string) error {
-	args := []string{s.logFilePath}
+// OpenLogFileInLnav opens a log file in `Lnav`. The filter is a Lnav expression used in the `:filter-expr` command. It is optional.
+func OpenLogFileInLnav(logFilePath string, filter string) error {
+	args := []string{logFilePath}
 
 	if filter != "" {
 		// Opens a specific service
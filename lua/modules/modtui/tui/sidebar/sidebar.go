@@ -7,8 +7,10 @@ import (
 
 	"github.com/LucasAVasco/falcula/lua/modules/modtui/tui/app"
 	"github.com/LucasAVasco/falcula/lua/modules/modtui/tui/keybinds"
+	"github.com/LucasAVasco/falcula/lua/modules/modtui/tui/usageview"
 	"github.com/LucasAVasco/falcula/service/enhanced"
 	"github.com/LucasAVasco/falcula/service/manager"
+	"github.com/LucasAVasco/falcula/service/status"
 
 	"github.com/rivo/tview"
 )
@@ -157,9 +159,22 @@ func (s *Sidebar) getServiceNode(man *manager.Manager, svc *enhanced.EnhancedSer
 
 // generateServiceText gets the text to show in the service node
 func (s *Sidebar) generateServiceText(svc *enhanced.EnhancedService) string {
+	if svc.GetStatus() == status.Running && svc.IsReady() {
+		return svc.GetName() + " (" + svc.GetStatus().ToString() + ", ready)"
+	}
+
 	return svc.GetName() + " (" + svc.GetStatus().ToString() + ")"
 }
 
+// getServiceUsage returns the resource usage samples to show below the service node. Only the running services show them
+func getServiceUsage(svc *enhanced.EnhancedService) []enhanced.Usage {
+	if svc.GetStatus() != status.Running {
+		return nil
+	}
+
+	return svc.GetUsageHistory()
+}
+
 // HasService checks if the side bar contains a service
 func (s *Sidebar) HasService(man *manager.Manager, svc *enhanced.EnhancedService) (bool, error) {
 	node, err := s.getServiceNode(man, svc)
@@ -191,6 +206,7 @@ func (s *Sidebar) AddService(man *manager.Manager, svc *enhanced.EnhancedService
 	// Creates the new service node and adds it to the manager node as a child
 	text := s.generateServiceText(svc)
 	newNode := tview.NewTreeNode(text).SetReference(svc).SetSelectable(true)
+	usageview.UpdateNode(newNode, getServiceUsage(svc))
 	managerNode.AddChild(newNode)
 
 	// Updates the UI
@@ -220,7 +236,7 @@ func (s *Sidebar) RemoveService(man *manager.Manager, svc *enhanced.EnhancedServ
 	return nil
 }
 
-// UpdateServiceStatus updates the status of a service in the side bar
+// UpdateServiceStatus updates the status and the resource usage of a service in the side bar
 func (s *Sidebar) UpdateServiceStatus(man *manager.Manager, svc *enhanced.EnhancedService) error {
 	// Gets the service node
 	node, err := s.getServiceNode(man, svc)
@@ -231,8 +247,9 @@ func (s *Sidebar) UpdateServiceStatus(man *manager.Manager, svc *enhanced.Enhanc
 		return fmt.Errorf("service '%s' not found in manager '%s", svc.GetName(), man.GetName())
 	}
 
-	// Updates the service text
+	// Updates the service text and resource usage
 	node.SetText(s.generateServiceText(svc))
+	usageview.UpdateNode(node, getServiceUsage(svc))
 
 	// Updates the UI
 	s.app.Draw()
@@ -0,0 +1,80 @@
+// Package usageview formats the resource usage of a service to show it in the sidebar (values and sparklines)
+package usageview
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/LucasAVasco/falcula/service/enhanced"
+	"github.com/LucasAVasco/falcula/units"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// sparklineSize is the number of samples shown in a sparkline
+const sparklineSize = 6
+
+// sparklineBlocks are the characters of a sparkline, from the lowest to the highest value
+var sparklineBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// UpdateNode shows the resource usage below a service node (as its child). The child is removed if there is no sample
+func UpdateNode(serviceNode *tview.TreeNode, history []enhanced.Usage) {
+	text := Format(history)
+	if text == "" {
+		serviceNode.ClearChildren()
+		return
+	}
+
+	children := serviceNode.GetChildren()
+	if len(children) == 0 {
+		serviceNode.AddChild(tview.NewTreeNode(text).SetSelectable(false).SetColor(tcell.ColorGray))
+		return
+	}
+
+	children[0].SetText(text)
+}
+
+// Format formats the last resource usage sample (CPU, memory, file descriptors and threads) with sparklines of the CPU and memory. The
+// history must be sorted from the oldest to the newest sample. Returns an empty string if there is no sample
+func Format(history []enhanced.Usage) string {
+	if len(history) == 0 {
+		return ""
+	}
+
+	if len(history) > sparklineSize {
+		history = history[len(history)-sparklineSize:]
+	}
+
+	cpu := make([]float64, 0, len(history))
+	memory := make([]float64, 0, len(history))
+	for _, usage := range history {
+		cpu = append(cpu, usage.CPUPercent)
+		memory = append(memory, float64(usage.RSS))
+	}
+
+	last := history[len(history)-1]
+	return fmt.Sprintf("%.0f%% %s %s %s %dfd %dthr", last.CPUPercent, sparkline(cpu, 100), units.FormatBytes(int64(last.RSS)),
+		sparkline(memory, 0), last.FDs, last.Threads)
+}
+
+// sparkline draws the values with block characters. The values are scaled to the maximum value (or to the provided minimum scale if it is
+// greater)
+func sparkline(values []float64, minScale float64) string {
+	scale := minScale
+	for _, value := range values {
+		scale = max(scale, value)
+	}
+
+	builder := strings.Builder{}
+	for _, value := range values {
+		index := 0
+		if scale > 0 {
+			index = int(value / scale * float64(len(sparklineBlocks)-1))
+		}
+
+		builder.WriteRune(sparklineBlocks[min(max(index, 0), len(sparklineBlocks)-1)])
+	}
+
+	return builder.String()
+}
@@ -2,6 +2,7 @@
 
 ---@class FalculaCmd Module to access the command line arguments.
 ---@field args string[] The command line arguments provided by the user with `falcula run`, `falcula run-raw` or the TUI.
+---@field params table<string, string|number|boolean> The parameter values of the script or task (defined with `params` in the `falcula.yaml` file).
 local M = {}
 
 ---Set the available command line arguments.
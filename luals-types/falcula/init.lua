@@ -0,0 +1,20 @@
+---@meta
+
+---@class Falcula General functions to configure Falcula.
+local M = {}
+
+---Configure whether the script should abort on error or return the error message as the last return value of the function.
+---@param abort boolean `true` to abort the script on error.
+function M.configure_abort_on_error(abort) end
+
+---Configure the behavior of the services log queue when it is full.
+---With `block` (default), the services wait until there is space in the queue. With `drop`, the new lines are dropped, so a slow log
+---consumer (e.g. the TUI) never stalls the services.
+---@param policy 'block'|'drop' The policy to use.
+function M.configure_log_policy(policy) end
+
+---Get the number of services log lines dropped because the log queue was full.
+---@return integer
+function M.get_dropped_log_lines() end
+
+return M
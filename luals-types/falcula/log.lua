@@ -0,0 +1,29 @@
+---@meta
+
+---@class FalculaLog Module to configure the destinations (sinks) of the service logs.
+local M = {}
+
+---@class FalculaLogSinkConfig Configuration of a log sink.
+---@field type 'syslog'|'jsonl'|'stdout'|'per_service'|'ring' Type of the sink.
+---@field name? string Name of the sink. Required to get the entries of a `ring` sink.
+---@field path? string File path (directory path for `per_service` sinks). Relative to the current Lua file.
+---@field filter? string Glob matched against the service name (e.g. `api.*`). All services if not provided.
+---@field size? integer Maximum number of entries of a `ring` sink.
+
+---@class FalculaLogEntry A service log line.
+---@field time string Time of the log (RFC 3339).
+---@field service string Name of the service.
+---@field stream string Stream of the log (e.g. `stdout`, `stderr`).
+---@field level? string Level parsed from the log (e.g. `info`, `error`). Only available if the service has a `log_format`.
+---@field message string Log line.
+
+---Add a destination to the service logs. The sink is removed when the script ends (or is re-run).
+---@param config FalculaLogSinkConfig Configuration of the sink.
+function M.add_sink(config) end
+
+---Get the entries of a `ring` sink, from the oldest to the newest.
+---@param name string Name of the sink.
+---@return FalculaLogEntry[]
+function M.get_entries(name) end
+
+return M
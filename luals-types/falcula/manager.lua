@@ -25,6 +25,11 @@ function M.ServiceManager:add_service(service) end
 ---@param services FalculaServiceService[] The services to add to the manager.
 function M.ServiceManager:add_services(services) end
 
+---Get a service of the manager.
+---@param name string Name of the service.
+---@return FalculaManagerService
+function M.ServiceManager:get_service(name) end
+
 ---Start the prepare phase of the services in the manager.
 function M.ServiceManager:start_prepare() end
 
@@ -58,4 +63,59 @@ function M.ServiceManager:stop() end
 ---Close the manager. You can not use the manager anymore after this function is called.
 function M.ServiceManager:close() end
 
+---@class FalculaManagerService A service added to a manager. Its functions must be called as methods (with `:`).
+local Service = {}
+
+---@return string
+function Service:get_name() end
+
+---@return string status Current status (e.g. `Running`, `Stopped`).
+function Service:get_status() end
+
+---Get a metadata of the service (e.g. a port discovered in its logs).
+---@param key string
+---@return string?
+function Service:get_metadata(key) end
+
+---@return table<string, string>
+function Service:get_all_metadata() end
+
+---@param key string
+---@param value string
+function Service:set_metadata(key, value) end
+
+---Check if the running service is marked as ready (different from the `Ready` status, that means the service is prepared).
+---@return boolean
+function Service:is_ready() end
+
+---Mark the running service as ready. The mark is cleared when the service starts again.
+function Service:set_ready() end
+
+---Wait until the service is marked as ready.
+---@param timeout? number Timeout in seconds. Waits forever if not provided.
+---@return boolean ready `false` if the timeout expired.
+function Service:wait_ready(timeout) end
+
+---@class FalculaManagerServiceStats Statistics and resource usage of a service.
+---@field cpu? number CPU usage in percent of one core. Nil if the service is not running (or its resource usage is not sampled).
+---@field memory? number Resident memory in bytes. Nil if the service is not running.
+---@field fds? number Open file descriptors. Nil if the service is not running.
+---@field threads? number Threads. Nil if the service is not running.
+---@field restarts number Number of restarts.
+---@field uptime number For how long the service is running, in seconds (duration of the last execution if it is not running).
+---@field exit_code? number Exit code of the last step that ended.
+
+---Get the statistics of the service. The resource usage is the sum of the processes of the service and their children, sampled at the
+---interval of the `resources.sample_interval` option of the `falcula.yaml` file.
+---@return FalculaManagerServiceStats
+function Service:stats() end
+
+---Restart the service.
+---@param force? boolean Force the stop instead of a graceful shutdown.
+function Service:restart(force) end
+
+---Stop the service (or abort its prepare step).
+---@param force? boolean Force the stop instead of a graceful shutdown.
+function Service:stop(force) end
+
 return M
@@ -3,13 +3,23 @@
 ---@class FalculaProcess Operational system processes module.
 local M = {}
 
+---@class FalculaProcessCommand: string[] Command and its arguments.
+---@field dir? string Working directory.
+---@field limits? FalculaProcessLimits Resource limits of the process (Unix only).
+
+---@class FalculaProcessLimits Resource limits applied with setrlimit. The children of the process inherit them.
+---@field memory? number|string Maximum virtual memory in bytes or size with unit (e.g. `'2G'`).
+---@field cpu_seconds? integer Maximum CPU time in seconds. The process is killed when it is exceeded.
+---@field nofile? integer Maximum number of open file descriptors.
+---@field nproc? integer Maximum number of processes of the user.
+
 ---@class FalculaProcessProvider Service provider for operational system processes.
 M.Provider = {}
 
 ---Create a new process service provider.
 ---@param name string Name of the service.
----@param prepare_cmd? string|string[] Command to run before the main command. Ignored if `nil`.
----@param main_cmd? string|string[] Command to run. Ignored if `nil`.
+---@param prepare_cmd? string|FalculaProcessCommand Command to run before the main command. Ignored if `nil`.
+---@param main_cmd? string|FalculaProcessCommand Command to run. Ignored if `nil`.
 ---@param opts? FalculaServiceProviderOpts Options for the provider.
 ---@return FalculaProcessProvider
 function M.Provider:new(name, prepare_cmd, main_cmd, opts) end
@@ -0,0 +1,24 @@
+---@meta
+
+---@class FalculaSecret Module to register secrets. The secrets are masked (replaced by `[REDACTED]`) in all logs before they are written to
+---the TUI, the session log file or any other sink.
+local M = {}
+
+---Register a secret value. Values shorter than 4 characters are ignored.
+---@param value string Secret value.
+function M.register(value) end
+
+---Register the values of the environment variables whose names match any of the globs.
+---@param globs string[] Globs matched against the environment variable names (e.g. `{ '*_TOKEN', '*_PASSWORD' }`).
+function M.register_env(globs) end
+
+---Register a regular expression (Go syntax). If it has groups, only the groups are masked, otherwise the whole match is masked.
+---@param pattern string Regular expression (e.g. `password=(\S+)`).
+function M.register_pattern(pattern) end
+
+---Mask the registered secrets of a text.
+---@param text string Text to redact.
+---@return string redacted
+function M.redact(text) end
+
+return M
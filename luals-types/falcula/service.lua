@@ -5,6 +5,20 @@ local M = {}
 
 ---@class FalculaServiceServiceOpts Service options.
 ---@field start_disabled? boolean If the service should not be automatically started. The user must enable the service manually.
+---@field log_format? 'json'|'logfmt'|string Format of the service logs (`json`, `logfmt` or `regex:<pattern>` with the named groups `level`, `message` and `time`). The level, message and timestamp are extracted from the parsed logs.
+---@field on_output? FalculaServiceOutputTrigger[] Triggers evaluated on each log line of the service.
+---@field thresholds? FalculaServiceResourceThreshold[] Actions when the resource usage of the service is too high.
+
+---@class FalculaServiceOutputTrigger Runs an action when a log line of the service matches a pattern.
+---@field pattern string Lua pattern matched against each log line (e.g. `'Listening on :(%d+)'`).
+---@field action? 'ready'|'restart'|'stop'|fun(svc: FalculaManagerService, ...: string) Action to run. Functions receive the service and the captures and run while the script waits (e.g. in `manager:wait()`) or after it returns.
+---@field metadata? string Saves the first capture (or the whole line if there are no captures) in the service metadata with this key.
+
+---@class FalculaServiceResourceThreshold Runs an action when the resource usage of the service (sum of its processes and their children) exceeds a maximum.
+---@field resource 'cpu'|'memory'|'fds'|'threads' Resource to check. The CPU usage is in percent of one core.
+---@field max number|string Maximum value. The memory can also be a size with unit (e.g. `'512M'`).
+---@field samples? integer Number of consecutive samples above the maximum required to run the action. Default: 1.
+---@field action? 'warn'|'restart' Logs a warning (default) or restarts the service.
 
 ---@class FalculaServiceService Generic service.
 
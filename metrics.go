@@ -0,0 +1,27 @@
+package falcula
+
+import (
+	"fmt"
+
+	"github.com/LucasAVasco/falcula/lua/luaruntime"
+	"github.com/LucasAVasco/falcula/metrics"
+)
+
+// startMetricsServer starts the metrics listener of the session. Does nothing and returns nil if the metrics are disabled
+func (a *App) startMetricsServer(runtime *luaruntime.Runtime) (*metrics.Server, error) {
+	if a.metricsAddr == "" {
+		return nil, nil
+	}
+
+	server, err := metrics.New(&metrics.Options{
+		Address: a.metricsAddr,
+		Runtime: runtime,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error starting metrics listener: %w", err)
+	}
+
+	runtime.Logger.LogDebug(fmt.Sprintf("Metrics listening on %s\n", server.GetURL()))
+
+	return server, nil
+}
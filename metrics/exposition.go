@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Types of the metric families
+const (
+	typeGauge   = "gauge"
+	typeCounter = "counter"
+)
+
+// label is a label of a sample
+type label struct {
+	name  string
+	value string
+}
+
+// sample is a value of a metric family
+type sample struct {
+	labels []label
+	value  float64
+}
+
+// family is a group of samples with the same name, help and type
+type family struct {
+	name    string
+	help    string
+	typ     string
+	samples []sample
+}
+
+// add adds a sample to the family. The labels are pairs of names and values
+func (f *family) add(value float64, labels ...string) {
+	s := sample{value: value}
+	for i := 0; i+1 < len(labels); i += 2 {
+		s.labels = append(s.labels, label{name: labels[i], value: labels[i+1]})
+	}
+
+	f.samples = append(f.samples, s)
+}
+
+// labelEscaper escapes the label values of the text exposition format
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// helpEscaper escapes the help texts of the text exposition format
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+// write writes the family in the Prometheus text exposition format. Families without samples are omitted
+func (f *family) write(w io.Writer) error {
+	if len(f.samples) == 0 {
+		return nil
+	}
+
+	builder := strings.Builder{}
+	fmt.Fprintf(&builder, "# HELP %s %s\n", f.name, helpEscaper.Replace(f.help))
+	fmt.Fprintf(&builder, "# TYPE %s %s\n", f.name, f.typ)
+
+	for _, s := range f.samples {
+		builder.WriteString(f.name)
+
+		if len(s.labels) > 0 {
+			builder.WriteByte('{')
+			for i, l := range s.labels {
+				if i > 0 {
+					builder.WriteByte(',')
+				}
+				fmt.Fprintf(&builder, `%s="%s"`, l.name, labelEscaper.Replace(l.value))
+			}
+			builder.WriteByte('}')
+		}
+
+		builder.WriteByte(' ')
+		builder.WriteString(strconv.FormatFloat(s.value, 'g', -1, 64))
+		builder.WriteByte('\n')
+	}
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+
+	"github.com/LucasAVasco/falcula/logsink"
+)
+
+// logCounter is a log sink that counts the log lines of each service and stream
+type logCounter struct {
+	mutex  sync.Mutex
+	counts map[logStream]uint64
+}
+
+// logStream is a stream of a service
+type logStream struct {
+	service string
+	stream  string
+}
+
+// logStreamCount is the number of log lines of a stream
+type logStreamCount struct {
+	logStream
+	lines uint64
+}
+
+func newLogCounter() *logCounter {
+	return &logCounter{
+		counts: make(map[logStream]uint64),
+	}
+}
+
+func (c *logCounter) Write(entry *logsink.Entry) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.counts[logStream{service: entry.Service, stream: entry.Stream}]++
+	return nil
+}
+
+func (c *logCounter) Close() error {
+	return nil
+}
+
+// getCounts returns the number of lines of each stream, sorted by service and stream
+func (c *logCounter) getCounts() []logStreamCount {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	counts := make([]logStreamCount, 0, len(c.counts))
+	for stream, lines := range c.counts {
+		counts = append(counts, logStreamCount{logStream: stream, lines: lines})
+	}
+
+	slices.SortFunc(counts, func(a, b logStreamCount) int {
+		return cmp.Or(cmp.Compare(a.service, b.service), cmp.Compare(a.stream, b.stream))
+	})
+
+	return counts
+}
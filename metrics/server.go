@@ -0,0 +1,246 @@
+// Package metrics exposes the state of the services of a session in the Prometheus text exposition format, on a local HTTP listener
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/LucasAVasco/falcula/lua/luaruntime"
+	"github.com/LucasAVasco/falcula/netaddr"
+	"github.com/LucasAVasco/falcula/procstat"
+	"github.com/LucasAVasco/falcula/service/status"
+)
+
+// closeTimeout is the maximum time to wait for the pending requests when closing the server
+const closeTimeout = time.Second
+
+// contentType is the content type of the text exposition format
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// statuses are all the service statuses, exposed as a state set (the current status has the value 1)
+var statuses = []status.Status{
+	status.None,
+	status.Preparing,
+	status.Ready,
+	status.AbortingPrepare,
+	status.PrepareAborted,
+	status.Running,
+	status.Ended,
+	status.Stopping,
+	status.Stopped,
+	status.Disabled,
+	status.Error,
+}
+
+// Options is the server options
+type Options struct {
+	Address string              // Address to listen on (e.g. '127.0.0.1:9090'). A port without host listens on 127.0.0.1. Required
+	Runtime *luaruntime.Runtime // Runtime with the managers and the logger. Required
+}
+
+// Server serves the metrics of a session on the '/metrics' path
+type Server struct {
+	opts       Options
+	listener   net.Listener
+	httpServer *http.Server
+	logLines   *logCounter
+	done       chan struct{}
+}
+
+// New creates a server and starts listening on its address
+func New(opts *Options) (*Server, error) {
+	address, err := netaddr.GetListenAddress(opts.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("error listening on address '%s': %w", address, err)
+	}
+
+	s := &Server{
+		opts:     *opts,
+		listener: listener,
+		logLines: newLogCounter(),
+		done:     make(chan struct{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics", s.handleMetrics)
+	s.httpServer = &http.Server{Handler: mux}
+
+	err = s.opts.Runtime.Logger.AddSink("", s.logLines)
+	if err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("error adding log sink of metrics server: %w", err)
+	}
+
+	go func() {
+		defer close(s.done)
+		s.httpServer.Serve(listener)
+	}()
+
+	return s, nil
+}
+
+// GetURL returns the URL of the metrics
+func (s *Server) GetURL() string {
+	addr := s.listener.Addr().(*net.TCPAddr)
+
+	host := addr.IP.String()
+	if addr.IP.IsUnspecified() {
+		host = "localhost"
+	}
+
+	return "http://" + net.JoinHostPort(host, fmt.Sprint(addr.Port)) + "/metrics"
+}
+
+// Close stops the server. Can be called multiple times
+func (s *Server) Close() error {
+	s.opts.Runtime.Logger.RemoveSink(s.logLines)
+
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+
+	err := s.httpServer.Shutdown(ctx)
+	if err != nil {
+		s.httpServer.Close() // Pending requests after the timeout
+	}
+	<-s.done
+
+	if err != nil {
+		return fmt.Errorf("error shutting down metrics server: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+
+	for _, family := range s.collect() {
+		err := family.write(w)
+		if err != nil {
+			return
+		}
+	}
+}
+
+// collect returns the current value of all metrics
+func (s *Server) collect() []*family {
+	statusFamily := &family{
+		name: "falcula_service_status",
+		help: "Current status of the service (1 for the current status, 0 for the others).",
+		typ:  typeGauge,
+	}
+	readyFamily := &family{
+		name: "falcula_service_ready",
+		help: "Whether the running service is marked as ready.",
+		typ:  typeGauge,
+	}
+	restartsFamily := &family{
+		name: "falcula_service_restarts_total",
+		help: "Number of times the service was restarted.",
+		typ:  typeCounter,
+	}
+	exitCodeFamily := &family{
+		name: "falcula_service_last_exit_code",
+		help: "Exit code of the last step of the service that ended.",
+		typ:  typeGauge,
+	}
+	uptimeFamily := &family{
+		name: "falcula_service_uptime_seconds",
+		help: "For how long the service is running. Zero if it is not running.",
+		typ:  typeGauge,
+	}
+	cpuFamily := &family{
+		name: "falcula_service_cpu_seconds_total",
+		help: "User and system CPU time of the processes of the running service.",
+		typ:  typeCounter,
+	}
+	rssFamily := &family{
+		name: "falcula_service_resident_memory_bytes",
+		help: "Resident memory of the processes of the running service.",
+		typ:  typeGauge,
+	}
+	threadsFamily := &family{
+		name: "falcula_service_threads",
+		help: "Number of threads of the processes of the running service.",
+		typ:  typeGauge,
+	}
+	fdsFamily := &family{
+		name: "falcula_service_open_fds",
+		help: "Number of open file descriptors of the processes of the running service.",
+		typ:  typeGauge,
+	}
+	logLinesFamily := &family{
+		name: "falcula_service_log_lines_total",
+		help: "Number of log lines written by the service.",
+		typ:  typeCounter,
+	}
+
+	snapshot, err := procstat.NewSnapshot()
+	if err != nil {
+		snapshot = nil // No '/proc' file system. The resource usage is not available
+	}
+
+	for _, man := range s.opts.Runtime.GetManagers() {
+		for _, svc := range man.GetServices() {
+			labels := []string{"manager", man.GetName(), "service", svc.GetName()}
+
+			svcStatus := svc.GetStatus()
+			for _, st := range statuses {
+				statusFamily.add(boolToFloat(st == svcStatus), slices.Concat(labels, []string{"status", st.ToString()})...)
+			}
+
+			readyFamily.add(boolToFloat(svc.IsReady()), labels...)
+
+			stats := svc.GetStats()
+			restartsFamily.add(float64(stats.Restarts), labels...)
+			if stats.LastExitInfo != nil {
+				exitCodeFamily.add(float64(stats.LastExitInfo.Code), labels...)
+			}
+
+			uptime := time.Duration(0)
+			if svcStatus == status.Running {
+				uptime = svc.GetUptime()
+			}
+			uptimeFamily.add(uptime.Seconds(), labels...)
+
+			// Processes (only the services that run system processes)
+			pids := svc.GetPids()
+			if len(pids) == 0 || snapshot == nil {
+				continue
+			}
+
+			usage := snapshot.ReadTree(pids...)
+
+			cpuFamily.add(usage.CPUTime.Seconds(), labels...)
+			rssFamily.add(float64(usage.RSS), labels...)
+			threadsFamily.add(float64(usage.Threads), labels...)
+			fdsFamily.add(float64(usage.FDs), labels...)
+		}
+	}
+
+	for _, count := range s.logLines.getCounts() {
+		logLinesFamily.add(float64(count.lines), "service", count.service, "stream", count.stream)
+	}
+
+	return []*family{
+		statusFamily, readyFamily, restartsFamily, exitCodeFamily, uptimeFamily, cpuFamily, rssFamily, threadsFamily, fdsFamily,
+		logLinesFamily,
+	}
+}
+
+func boolToFloat(value bool) float64 {
+	if value {
+		return 1
+	}
+
+	return 0
+}
@@ -1,10 +1,21 @@
 package multiplexer
 
 import (
+	"sync"
+	"sync/atomic"
+
 	"github.com/fatih/color"
 )
 
-// Client is a multiplexer client that can write to the multiplexer. Multiple clients can write to the same multiplexer
+// MaxLineSize is the maximum size of a line buffered by a client. If a line is bigger than this value, it is sent to the multiplexer in
+// pieces of this size
+const MaxLineSize = 64 * 1024
+
+// Client is a multiplexer client that can write to the multiplexer. Multiple clients can write to the same multiplexer.
+//
+// The client assembles lines: the data written to it is buffered until a newline is found, so a line split across multiple `Write` calls
+// is sent to the multiplexer as a single line. Carriage returns not followed by a newline (progress bars) discard the current line content,
+// so only the last version of the line is sent. The remaining data is sent when the client is flushed
 type Client struct {
 	multi *Multiplexer // Multiplexer that created this client
 
@@ -12,6 +23,12 @@ type Client struct {
 	level string
 	clr   *color.Color
 	id    uint
+
+	mutex     sync.Mutex // Ensures only one write is processed at a time by this client
+	buffer    []byte     // Current line. Not terminated by a newline yet
+	pendingCR bool       // The last processed byte is a carriage return
+
+	droppedLines atomic.Uint64
 }
 
 func (c *Client) GetName() string {
@@ -30,9 +47,70 @@ func (c *Client) GetColor() *color.Color {
 	return c.clr
 }
 
+// GetDroppedLines returns the number of lines of this client dropped by the multiplexer
+func (c *Client) GetDroppedLines() uint64 {
+	return c.droppedLines.Load()
+}
+
+// Write implements the `io.Writer` interface. Sends each complete line to the multiplexer and buffers the incomplete one
 func (c *Client) Write(p []byte) (n int, err error) {
-	c.multi.mutex.Lock()
-	defer c.multi.mutex.Unlock()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, b := range p {
+		switch b {
+		case '\n':
+			c.pendingCR = false
+			err = c.sendLine(err)
+
+		case '\r':
+			c.pendingCR = true
+
+		default:
+			// The line is being rewritten (e.g. progress bar). Only its last version is relevant
+			if c.pendingCR {
+				c.pendingCR = false
+				c.buffer = c.buffer[:0]
+			}
+
+			c.buffer = append(c.buffer, b)
+			if len(c.buffer) >= MaxLineSize {
+				err = c.sendLine(err)
+			}
+		}
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Flush sends the buffered incomplete line (if any) to the multiplexer. Should be called when the writer ends (e.g. the process exits)
+func (c *Client) Flush() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.pendingCR = false
+	if len(c.buffer) == 0 {
+		return nil
+	}
+
+	return c.sendLine(nil)
+}
+
+// sendLine sends the current line to the multiplexer and clears it. Returns the provided error if it is not nil, so the first error of a
+// write is preserved. Must be called with the client mutex locked
+func (c *Client) sendLine(prevErr error) error {
+	line := make([]byte, len(c.buffer))
+	copy(line, c.buffer)
+	c.buffer = c.buffer[:0]
+
+	err := c.multi.write(c, line)
+	if prevErr != nil {
+		return prevErr
+	}
 
-	return c.multi.callback(c, p)
+	return err
 }
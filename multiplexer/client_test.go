@@ -0,0 +1,140 @@
+package multiplexer
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// collect creates a multiplexer that stores the received lines. The lines are only safe to read after closing the multiplexer
+func collect() (*Multiplexer, *[]string) {
+	lines := []string{}
+	mutex := sync.Mutex{}
+
+	multi := New(func(entry *Entry) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		lines = append(lines, string(entry.Line))
+		return nil
+	}, nil)
+
+	return multi, &lines
+}
+
+func TestClientAssemblesLines(t *testing.T) {
+	long := strings.Repeat("x", MaxLineSize)
+
+	tests := []struct {
+		name   string
+		writes []string
+		flush  bool
+		want   []string
+	}{
+		{
+			name:   "single write",
+			writes: []string{"first\nsecond\n"},
+			want:   []string{"first", "second"},
+		},
+		{
+			name:   "line split across writes",
+			writes: []string{"fir", "st\nsec", "ond", "\n"},
+			want:   []string{"first", "second"},
+		},
+		{
+			name:   "one byte per write",
+			writes: strings.Split("ab\ncd\n", ""),
+			want:   []string{"ab", "cd"},
+		},
+		{
+			name:   "empty lines",
+			writes: []string{"\n\na\n"},
+			want:   []string{"", "", "a"},
+		},
+		{
+			name:   "CRLF",
+			writes: []string{"first\r\nsecond\r\n"},
+			want:   []string{"first", "second"},
+		},
+		{
+			name:   "CRLF split across writes",
+			writes: []string{"first\r", "\nsecond\r", "\n"},
+			want:   []string{"first", "second"},
+		},
+		{
+			name:   "carriage return rewrites the line",
+			writes: []string{"10%\r", "50%\r", "100%\n"},
+			want:   []string{"100%"},
+		},
+		{
+			name:   "final line without newline is not sent without flush",
+			writes: []string{"first\nlast"},
+			want:   []string{"first"},
+		},
+		{
+			name:   "final line without newline is sent by flush",
+			writes: []string{"first\nla", "st"},
+			flush:  true,
+			want:   []string{"first", "last"},
+		},
+		{
+			name:   "flush without pending line",
+			writes: []string{"first\n"},
+			flush:  true,
+			want:   []string{"first"},
+		},
+		{
+			name:   "overlong line is sent in pieces",
+			writes: []string{long + "tail\n"},
+			want:   []string{long, "tail"},
+		},
+		{
+			name:   "overlong line split across writes",
+			writes: []string{long[:100], long[100:] + "ta", "il\n"},
+			want:   []string{long, "tail"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			multi, lines := collect()
+			client := multi.NewClient("client", "stdout", nil)
+
+			for _, data := range test.writes {
+				n, err := client.Write([]byte(data))
+				if err != nil {
+					t.Fatalf("unexpected write error: %v", err)
+				}
+				if n != len(data) {
+					t.Fatalf("write returned %d, expected %d", n, len(data))
+				}
+			}
+
+			if test.flush {
+				err := client.Flush()
+				if err != nil {
+					t.Fatalf("unexpected flush error: %v", err)
+				}
+			}
+
+			multi.Close()
+
+			if !slices.Equal(*lines, test.want) {
+				t.Errorf("got lines %q, expected %q", shorten(*lines), shorten(test.want))
+			}
+		})
+	}
+}
+
+// shorten shortens the long lines, so the test errors are readable
+func shorten(lines []string) []string {
+	short := make([]string, len(lines))
+	for i, line := range lines {
+		if len(line) > 32 {
+			line = line[:16] + "..." + line[len(line)-8:]
+		}
+		short[i] = line
+	}
+	return short
+}
@@ -2,39 +2,153 @@
 package multiplexer
 
 import (
+	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/fatih/color"
 )
 
-// Callback is the callback function that is called when data is written to the multiplexer
-type Callback func(client *Client, b []byte) (int, error)
+// DefaultQueueSize is the default number of lines that can wait in the multiplexer queue before the policy is applied
+const DefaultQueueSize = 4096
 
-// Multiplexer is the log multiplexer. It generates clients that send logs to the log multiplexer
+// Policy is the behavior of the multiplexer when its queue is full
+type Policy int32
+
+const (
+	Block Policy = 0 // The writer waits until there is space in the queue
+	Drop  Policy = 1 // The line is dropped and counted as a dropped line
+)
+
+// ParsePolicy parses a policy name ('block' or 'drop')
+func ParsePolicy(name string) (Policy, error) {
+	switch name {
+	case "block":
+		return Block, nil
+	case "drop":
+		return Drop, nil
+	default:
+		return Block, fmt.Errorf("invalid multiplexer policy '%s', must be 'block' or 'drop'", name)
+	}
+}
+
+// Entry is a line written by a client to the multiplexer
+type Entry struct {
+	Client *Client
+	Line   []byte    // Line without the trailing newline
+	Time   time.Time // Time when the line was written by the client
+}
+
+// Callback is the callback function that is called when a line is written to the multiplexer. It is always called from the same goroutine
+// (the multiplexer consumer), so it does not need to be thread-safe with itself
+type Callback func(entry *Entry) error
+
+// Options is the multiplexer options. All fields are optional
+type Options struct {
+	QueueSize int              // Size of the queue. Uses `DefaultQueueSize` if not provided
+	Policy    Policy           // Behavior when the queue is full. Blocks by default
+	OnError   func(err error)  // Called when the callback returns an error
+	Now       func() time.Time // Function used to get the time of the entries. Uses `time.Now` if not provided
+}
+
+// Multiplexer is the log multiplexer. It generates clients that send logs to the log multiplexer.
+//
+// The clients do not call the callback directly. They send their lines to a buffered queue consumed by a single goroutine, so a slow
+// callback does not block the writers unless the queue is full and the policy is `Block`
 type Multiplexer struct {
-	callback     Callback   // Called when a log is written
-	mutex        sync.Mutex // Ensures only one log is written at a time
+	callback Callback // Called when a log is written
+	opts     Options
+	policy   atomic.Int32
+
+	queue      chan *Entry
+	queueMutex sync.RWMutex // Protects the queue against writes after closing it
+	closed     bool
+	consumer   sync.WaitGroup
+
+	clientMutex  sync.Mutex // Protects the client ID
 	nextClientId uint       // Client ID. Auto-incremented
+
+	droppedLines atomic.Uint64
 }
 
-func New(callback Callback) *Multiplexer {
+// New creates a new multiplexer and starts its consumer. The options are optional
+func New(callback Callback, opts *Options) *Multiplexer {
+	if opts == nil {
+		opts = &Options{}
+	}
+
 	m := Multiplexer{
-		mutex:        sync.Mutex{},
 		callback:     callback,
+		opts:         *opts,
 		nextClientId: 1,
 	}
 
+	if m.opts.QueueSize <= 0 {
+		m.opts.QueueSize = DefaultQueueSize
+	}
+
+	if m.opts.OnError == nil {
+		m.opts.OnError = func(err error) {}
+	}
+
+	if m.opts.Now == nil {
+		m.opts.Now = time.Now
+	}
+
+	m.policy.Store(int32(m.opts.Policy))
+	m.queue = make(chan *Entry, m.opts.QueueSize)
+
+	m.consumer.Go(m.consume)
+
 	return &m
 }
 
-// Close the multiplexer. Does not close the log file. The user must close the log file manually.
+// consume calls the callback for each entry in the queue until it is closed
+func (m *Multiplexer) consume() {
+	for entry := range m.queue {
+		err := m.callback(entry)
+		if err != nil {
+			m.opts.OnError(fmt.Errorf("error processing line of client '%s': %w", entry.Client.GetName(), err))
+		}
+	}
+}
+
+// Close the multiplexer. Waits until all queued lines are processed. Lines written after closing are dropped. Can be called multiple times.
+// Does not close the log file. The user must close the log file manually.
 func (m *Multiplexer) Close() error {
+	m.queueMutex.Lock()
+	if !m.closed {
+		m.closed = true
+		close(m.queue)
+	}
+	m.queueMutex.Unlock()
+
+	m.consumer.Wait()
 	return nil
 }
 
+// SetPolicy sets the behavior of the multiplexer when its queue is full. Can be called while the multiplexer is in use
+func (m *Multiplexer) SetPolicy(policy Policy) {
+	m.policy.Store(int32(policy))
+}
+
+// GetPolicy returns the behavior of the multiplexer when its queue is full
+func (m *Multiplexer) GetPolicy() Policy {
+	return Policy(m.policy.Load())
+}
+
+// GetDroppedLines returns the number of lines dropped by all clients because the queue was full or the multiplexer was closed
+func (m *Multiplexer) GetDroppedLines() uint64 {
+	return m.droppedLines.Load()
+}
+
 // NewClient generates a new client. The arguments of this functions are metadata that the client can access. The level has not a specific
 // format, but you should avoid using special characters and spaces (good examples: 'stdout', 'stderr', 'info', 'error', etc.).
 func (m *Multiplexer) NewClient(name string, level string, color *color.Color) *Client {
+	m.clientMutex.Lock()
+	defer m.clientMutex.Unlock()
+
 	w := Client{
 		multi: m,
 		name:  name,
@@ -48,3 +162,39 @@ func (m *Multiplexer) NewClient(name string, level string, color *color.Color) *
 
 	return &w
 }
+
+// write adds a complete line of a client to the queue. Applies the current policy if the queue is full
+func (m *Multiplexer) write(client *Client, line []byte) error {
+	entry := &Entry{
+		Client: client,
+		Line:   line,
+		Time:   m.opts.Now(),
+	}
+
+	m.queueMutex.RLock()
+	defer m.queueMutex.RUnlock()
+
+	if m.closed {
+		m.drop(client)
+		return nil
+	}
+
+	if m.GetPolicy() == Block {
+		m.queue <- entry
+		return nil
+	}
+
+	select {
+	case m.queue <- entry:
+	default:
+		m.drop(client)
+	}
+
+	return nil
+}
+
+// drop counts a dropped line
+func (m *Multiplexer) drop(client *Client) {
+	m.droppedLines.Add(1)
+	client.droppedLines.Add(1)
+}
@@ -0,0 +1,132 @@
+package multiplexer
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMultiplexerDropPolicy(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	received := atomic.Uint64{}
+
+	multi := New(func(entry *Entry) error {
+		if received.Add(1) == 1 {
+			close(started)
+			<-release // Blocks the consumer, so the queue fills up
+		}
+		return nil
+	}, &Options{QueueSize: 1, Policy: Drop})
+
+	first := multi.NewClient("first", "stdout", nil)
+	second := multi.NewClient("second", "stdout", nil)
+
+	// The consumer holds the first line and the queue holds the second one. The other lines are dropped
+	first.Write([]byte("held by the consumer\n"))
+	<-started
+	first.Write([]byte("queued\n"))
+
+	for range 3 {
+		first.Write([]byte("dropped\n"))
+	}
+	for range 5 {
+		second.Write([]byte("dropped\n"))
+	}
+
+	close(release)
+	multi.Close()
+
+	if got := first.GetDroppedLines(); got != 3 {
+		t.Errorf("first client dropped %d lines, expected 3", got)
+	}
+	if got := second.GetDroppedLines(); got != 5 {
+		t.Errorf("second client dropped %d lines, expected 5", got)
+	}
+	if got := multi.GetDroppedLines(); got != 8 {
+		t.Errorf("multiplexer dropped %d lines, expected 8", got)
+	}
+	if got := received.Load(); got != 2 {
+		t.Errorf("callback received %d lines, expected 2", got)
+	}
+
+	// Lines written after closing are dropped
+	second.Write([]byte("after close\n"))
+	if got := second.GetDroppedLines(); got != 6 {
+		t.Errorf("second client dropped %d lines after closing, expected 6", got)
+	}
+	if got := multi.GetDroppedLines(); got != 9 {
+		t.Errorf("multiplexer dropped %d lines after closing, expected 9", got)
+	}
+}
+
+func TestMultiplexerBlockPolicy(t *testing.T) {
+	const numClients = 8
+	const numLines = 1000
+
+	received := atomic.Uint64{}
+	multi := New(func(entry *Entry) error {
+		received.Add(1)
+		return nil
+	}, &Options{QueueSize: 1, Policy: Block})
+
+	writeConcurrently(multi, numClients, numLines, []byte("line\n"))
+	multi.Close()
+
+	if got := received.Load(); got != numClients*numLines {
+		t.Errorf("callback received %d lines, expected %d", got, numClients*numLines)
+	}
+	if got := multi.GetDroppedLines(); got != 0 {
+		t.Errorf("multiplexer dropped %d lines, expected 0", got)
+	}
+}
+
+// writeConcurrently writes the line multiple times (numLines) from each client (numClients). The clients write at the same time
+func writeConcurrently(multi *Multiplexer, numClients int, numLines int, line []byte) {
+	waitGroup := sync.WaitGroup{}
+	for i := range numClients {
+		client := multi.NewClient(fmt.Sprintf("client-%d", i), "stdout", nil)
+		waitGroup.Go(func() {
+			for range numLines {
+				client.Write(line)
+			}
+			client.Flush()
+		})
+	}
+	waitGroup.Wait()
+}
+
+// benchmarkMultiplexer measures the throughput of many noisy clients writing at the same time to a multiplexer with the provided policy
+func benchmarkMultiplexer(b *testing.B, policy Policy) {
+	line := []byte("2024-01-01T00:00:00Z INFO a noisy process writing a log line\n")
+
+	for _, numClients := range []int{1, 8, 64} {
+		b.Run(fmt.Sprintf("clients=%d", numClients), func(b *testing.B) {
+			// The callback formats the line, as the sinks do
+			multi := New(func(entry *Entry) error {
+				_, err := fmt.Fprintf(io.Discard, "%s | %s\n", entry.Client.GetName(), entry.Line)
+				return err
+			}, &Options{Policy: policy})
+			linesPerClient := max(b.N/numClients, 1)
+
+			b.SetBytes(int64(len(line)))
+			b.ResetTimer()
+
+			writeConcurrently(multi, numClients, linesPerClient, line)
+			multi.Close()
+
+			b.StopTimer()
+			b.ReportMetric(float64(multi.GetDroppedLines())/float64(linesPerClient*numClients), "dropped/line")
+		})
+	}
+}
+
+func BenchmarkMultiplexerBlock(b *testing.B) {
+	benchmarkMultiplexer(b, Block)
+}
+
+func BenchmarkMultiplexerDrop(b *testing.B) {
+	benchmarkMultiplexer(b, Drop)
+}
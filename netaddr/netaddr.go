@@ -0,0 +1,27 @@
+// Package netaddr is a collection of functions related to the network addresses of the local servers (web dashboard and metrics)
+package netaddr
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// GetListenAddress returns the address to listen on. A port without host (e.g. ':7777' or '7777') listens on 127.0.0.1, so the server is
+// only available to other machines if a host is explicitly provided (e.g. '0.0.0.0:7777')
+func GetListenAddress(address string) (string, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		host, port = "", address // Only the port
+	}
+
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return "", fmt.Errorf("invalid address '%s', must be '[host]:port'", address)
+	}
+
+	if host == "" {
+		host = "127.0.0.1"
+	}
+
+	return net.JoinHostPort(host, port), nil
+}
@@ -30,7 +30,8 @@ type ExitCode int
 type ExitInfo struct {
 	Code    ExitCode
 	Error   error
-	Stopped bool // Manually stopped by the user
+	Stopped bool   // Manually stopped by the user
+	Limit   string // Resource limit that killed the process (e.g. `LimitMemory`). Empty if it was not killed by a limit
 }
 
 // HasError checks if the process has exited with an error (checks the exit code and the process error)
@@ -48,5 +49,9 @@ func (e *ExitInfo) WrapError() error {
 		return nil
 	}
 
+	if e.Limit != "" {
+		return fmt.Errorf("killed after exceeding the '%s' limit, exit code: %d, error: %w", e.Limit, e.Code, e.Error)
+	}
+
 	return fmt.Errorf("exit code: %d, error: %w", e.Code, e.Error)
 }
@@ -0,0 +1,88 @@
+package process
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/LucasAVasco/falcula/units"
+)
+
+// LimitsHelperArg is the first argument of the helper that applies the resource limits. The Falcula executable runs the helper (see
+// `RunLimitsHelper`) when it receives this argument
+const LimitsHelperArg = "__falcula-limits"
+
+// Names of the resource limits. Used in the Lua API, in the helper arguments and in `ExitInfo.Limit`
+const (
+	LimitMemory     = "memory"
+	LimitCPUSeconds = "cpu_seconds"
+	LimitNoFile     = "nofile"
+	LimitNProc      = "nproc"
+)
+
+// Limits are the resource limits of a process, applied with setrlimit before running the command (the children of the process inherit
+// them). Zero values are not limited
+type Limits struct {
+	Memory     uint64 // Maximum size of the virtual memory in bytes (RLIMIT_AS)
+	CPUSeconds uint64 // Maximum CPU time in seconds (RLIMIT_CPU). The process receives SIGXCPU when it is reached and SIGKILL 1 second later
+	NoFile     uint64 // Maximum number of open file descriptors (RLIMIT_NOFILE)
+	NProc      uint64 // Maximum number of processes of the user (RLIMIT_NPROC)
+}
+
+// IsEmpty checks if no resource is limited
+func (l *Limits) IsEmpty() bool {
+	return l == nil || *l == Limits{}
+}
+
+// String formats the limits as `name=value` pairs separated by spaces (e.g. 'memory=2.0G cpu_seconds=600')
+func (l *Limits) String() string {
+	pairs := []string{}
+	if l.Memory > 0 {
+		pairs = append(pairs, LimitMemory+"="+units.FormatBytes(int64(l.Memory)))
+	}
+	if l.CPUSeconds > 0 {
+		pairs = append(pairs, LimitCPUSeconds+"="+strconv.FormatUint(l.CPUSeconds, 10))
+	}
+	if l.NoFile > 0 {
+		pairs = append(pairs, LimitNoFile+"="+strconv.FormatUint(l.NoFile, 10))
+	}
+	if l.NProc > 0 {
+		pairs = append(pairs, LimitNProc+"="+strconv.FormatUint(l.NProc, 10))
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+// encode encodes the limits in a single helper argument (e.g. 'memory=2147483648,cpu_seconds=600')
+func (l *Limits) encode() string {
+	return fmt.Sprintf("%s=%d,%s=%d,%s=%d,%s=%d", LimitMemory, l.Memory, LimitCPUSeconds, l.CPUSeconds, LimitNoFile, l.NoFile, LimitNProc,
+		l.NProc)
+}
+
+// decodeLimits decodes the limits encoded by `Limits.encode`
+func decodeLimits(encoded string) (*Limits, error) {
+	limits := Limits{}
+
+	for pair := range strings.SplitSeq(encoded, ",") {
+		name, value, _ := strings.Cut(pair, "=")
+		number, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of the '%s' limit: %w", name, err)
+		}
+
+		switch name {
+		case LimitMemory:
+			limits.Memory = number
+		case LimitCPUSeconds:
+			limits.CPUSeconds = number
+		case LimitNoFile:
+			limits.NoFile = number
+		case LimitNProc:
+			limits.NProc = number
+		default:
+			return nil, fmt.Errorf("unknown limit '%s'", name)
+		}
+	}
+
+	return &limits, nil
+}
@@ -0,0 +1,26 @@
+//go:build !unix
+
+package process
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// wrapCmdWithLimits returns an error. The resource limits are not supported in this platform
+func wrapCmdWithLimits(cmd *exec.Cmd, limits *Limits) error {
+	return errors.New("resource limits are only supported on Unix systems")
+}
+
+// RunLimitsHelper exits with an error. The resource limits are not supported in this platform
+func RunLimitsHelper(args []string) {
+	fmt.Fprintln(os.Stderr, "falcula: resource limits are only supported on Unix systems")
+	os.Exit(127)
+}
+
+// getExceededLimit returns an empty string. The resource limits are not supported in this platform
+func getExceededLimit(state *os.ProcessState, limits *Limits) string {
+	return ""
+}
@@ -0,0 +1,105 @@
+//go:build unix
+
+package process
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// wrapCmdWithLimits replaces the command by the limits helper, that applies the limits and then executes the command (Go can not run code
+// in the child process before the exec). The helper is executed with the same PID as the command, so it can be stopped as usual
+func wrapCmdWithLimits(cmd *exec.Cmd, limits *Limits) error {
+	if cmd.Err != nil { // Command not found. Reported by `cmd.Start`
+		return nil
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("error getting the Falcula executable: %w", err)
+	}
+
+	cmd.Args = append([]string{executable, LimitsHelperArg, limits.encode(), cmd.Path}, cmd.Args...)
+	cmd.Path = executable
+	return nil
+}
+
+// RunLimitsHelper applies the resource limits and executes the command. Receives the arguments after `LimitsHelperArg`: the encoded
+// limits, the command path and the command arguments (including the command name). Does not return
+func RunLimitsHelper(args []string) {
+	err := runLimitsHelper(args)
+	fmt.Fprintf(os.Stderr, "falcula: error running command with resource limits: %v\n", err)
+	os.Exit(127)
+}
+
+// runLimitsHelper applies the resource limits and executes the command. Only returns on error
+func runLimitsHelper(args []string) error {
+	if len(args) < 3 {
+		return fmt.Errorf("expected the limits, the command path and the command arguments, got %d arguments", len(args))
+	}
+
+	limits, err := decodeLimits(args[0])
+	if err != nil {
+		return fmt.Errorf("error decoding limits: %w", err)
+	}
+
+	rlimits := []struct {
+		resource int
+		name     string
+		soft     uint64
+		hard     uint64
+	}{
+		{unix.RLIMIT_AS, LimitMemory, limits.Memory, limits.Memory},
+		{unix.RLIMIT_CPU, LimitCPUSeconds, limits.CPUSeconds, limits.CPUSeconds + 1}, // SIGXCPU, then SIGKILL
+		{unix.RLIMIT_NOFILE, LimitNoFile, limits.NoFile, limits.NoFile},
+		{unix.RLIMIT_NPROC, LimitNProc, limits.NProc, limits.NProc},
+	}
+
+	for _, rlimit := range rlimits {
+		if rlimit.soft == 0 {
+			continue
+		}
+
+		err := unix.Setrlimit(rlimit.resource, &unix.Rlimit{Cur: rlimit.soft, Max: rlimit.hard})
+		if err != nil {
+			return fmt.Errorf("error setting the '%s' limit: %w", rlimit.name, err)
+		}
+	}
+
+	return unix.Exec(args[1], args[2:], os.Environ())
+}
+
+// getExceededLimit returns the name of the limit that probably killed the process. Returns an empty string if the process was not killed
+// by a limit. A process is killed by the CPU limit with SIGXCPU or SIGKILL (after using all its CPU time). Allocations above the memory
+// limit fail, so the processes killed by SIGSEGV, SIGBUS or SIGABRT with a memory limit are reported as killed by the memory limit
+func getExceededLimit(state *os.ProcessState, limits *Limits) string {
+	if state == nil || limits.IsEmpty() {
+		return ""
+	}
+
+	status, ok := state.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return ""
+	}
+
+	switch status.Signal() {
+	case syscall.SIGXCPU:
+		if limits.CPUSeconds > 0 {
+			return LimitCPUSeconds
+		}
+	case syscall.SIGKILL:
+		if limits.CPUSeconds > 0 && (state.UserTime()+state.SystemTime()).Seconds() >= float64(limits.CPUSeconds) {
+			return LimitCPUSeconds
+		}
+	case syscall.SIGSEGV, syscall.SIGBUS, syscall.SIGABRT:
+		if limits.Memory > 0 {
+			return LimitMemory
+		}
+	}
+
+	return ""
+}
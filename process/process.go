@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"sync"
+	"sync/atomic"
 
 	"github.com/LucasAVasco/falcula/colorgen"
 	"github.com/LucasAVasco/falcula/multiplexer"
@@ -19,8 +20,14 @@ type OnExitCallback func(info *ExitInfo)
 type Process struct {
 	cmd *exec.Cmd
 
-	started bool
-	onExit  OnExitCallback
+	started  bool
+	recorded bool         // Recorded instead of spawned (dry run). Ends successfully as soon as it starts
+	limits   *Limits      // Resource limits applied by the limits helper. Nil if not limited
+	pid      atomic.Int64 // PID of the running process. Zero if it is not running
+	onExit   OnExitCallback
+
+	stdout *multiplexer.Client
+	stderr *multiplexer.Client
 
 	waitGroup sync.WaitGroup // `Wait` method
 	exitInfo  ExitInfo       // `Wait` method
@@ -37,6 +44,9 @@ type Options struct {
 	Multiplexer *multiplexer.Multiplexer // Multiplexer used for logging
 	Name        string                   // Name of the process used for logging
 	Color       *color.Color             // Color used for logging
+
+	Recorder Recorder // Records the process instead of spawning it (dry run). Optional
+	Limits   *Limits  // Resource limits of the process (Unix only). Optional
 }
 
 // CreateCmd creates a new `exec.Cmd` with supported to run it in a shell (if `shell` is true)
@@ -75,10 +85,27 @@ func New(opts *Options, command string, args ...string) (*Process, error) {
 	}
 
 	// Standard output client
-	p.cmd.Stdout = opts.Multiplexer.NewClient(opts.Name, "stdout", color)
+	p.stdout = opts.Multiplexer.NewClient(opts.Name, "stdout", color)
+	p.cmd.Stdout = p.stdout
 
 	// Standard error client
-	p.cmd.Stderr = opts.Multiplexer.NewClient(opts.Name, "stderr", color)
+	p.stderr = opts.Multiplexer.NewClient(opts.Name, "stderr", color)
+	p.cmd.Stderr = p.stderr
+
+	// Dry run
+	if opts.Recorder != nil {
+		opts.Recorder.RecordProcess(newRecord(opts, &p))
+		p.recorded = true
+	}
+
+	// Resource limits
+	if !p.recorded && !opts.Limits.IsEmpty() {
+		err := wrapCmdWithLimits(p.cmd, opts.Limits)
+		if err != nil {
+			return nil, fmt.Errorf("error applying resource limits: %w", err)
+		}
+		p.limits = opts.Limits
+	}
 
 	// Starts the process
 	p.waitGroup.Add(1) // NOTE(LucasAVasco): Will be done when the process ends (see the routine in `Start`)
@@ -108,11 +135,24 @@ func (p *Process) Start() error {
 	}
 	p.started = true
 
+	if p.recorded {
+		go func() {
+			defer p.waitGroup.Done()
+
+			if p.onExit != nil {
+				p.onExit(&p.exitInfo)
+			}
+		}()
+
+		return nil
+	}
+
 	// Starts the command
 	err := p.cmd.Start()
 	if err != nil {
 		return fmt.Errorf("error starting process: %w", err)
 	}
+	p.pid.Store(int64(p.cmd.Process.Pid))
 
 	// Routine to wait for the process to end and get the exit code
 	// NOTE(LucasAVasco): the `waitGroup.Add` method is called in the `New` method, can not call it again
@@ -120,11 +160,17 @@ func (p *Process) Start() error {
 		defer p.waitGroup.Done()
 
 		err := p.cmd.Wait()
+		p.pid.Store(0)
 		if err != nil && !p.Stopped() { // If we stopped the process, it will return an error. We should not display it
 			p.exitInfo.Error = err
 			p.exitInfo.Code = GetExitCodeFromError(err)
+			p.exitInfo.Limit = getExceededLimit(p.cmd.ProcessState, p.limits)
 		}
 
+		// Sends the last lines that do not end with a newline
+		p.stdout.Flush()
+		p.stderr.Flush()
+
 		if p.onExit != nil {
 			p.onExit(&p.exitInfo)
 		}
@@ -137,6 +183,11 @@ func (p *Process) Started() bool {
 	return p.started
 }
 
+// GetPid returns the PID of the process. Returns 0 if the process is not running
+func (p *Process) GetPid() int {
+	return int(p.pid.Load())
+}
+
 // Wait until for the process to end.
 //
 // Return the exit code of the process and an error.
@@ -162,6 +213,10 @@ func (p *Process) GetExitError() error {
 //
 // The `force` parameter will force the process to be killed (send SIGKILL instead of executing a graceful shutdown)
 func (p *Process) Kill(force bool) error {
+	if p.recorded {
+		return nil
+	}
+
 	if p.cmd.ProcessState != nil {
 		if p.cmd.ProcessState.Exited() {
 			return nil
@@ -202,6 +257,10 @@ func (p *Process) Stop(force bool) (*ExitInfo, error) {
 
 // Exited checks if the process has exited. If the process has not been started yet, this method will return false
 func (p *Process) Exited() bool {
+	if p.recorded {
+		return p.started
+	}
+
 	if p.cmd.ProcessState == nil {
 		return false
 	}
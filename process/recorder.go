@@ -0,0 +1,36 @@
+package process
+
+import (
+	"os"
+)
+
+// Recorder records the processes instead of spawning them (dry run). See `Options.Recorder`
+type Recorder interface {
+	RecordProcess(record *Record)
+}
+
+// Record is a process that would be spawned by `New`
+type Record struct {
+	Name   string   // Name of the process (`Options.Name`)
+	Args   []string // Command and its arguments. Shell commands are wrapped by the shell command (e.g. `sh -c <command>`)
+	Dir    string   // Working directory
+	Env    []string // Environment variables added to the process (`Options.Env`). Does not include the inherited ones
+	Limits *Limits  // Resource limits (`Options.Limits`). Nil if not limited
+}
+
+// newRecord creates the record of a process created by `New`
+func newRecord(opts *Options, p *Process) *Record {
+	record := Record{
+		Name:   opts.Name,
+		Args:   p.cmd.Args,
+		Dir:    p.cmd.Dir,
+		Env:    opts.Env,
+		Limits: opts.Limits,
+	}
+
+	if record.Dir == "" {
+		record.Dir, _ = os.Getwd()
+	}
+
+	return &record
+}
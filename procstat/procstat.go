@@ -0,0 +1,168 @@
+// Package procstat reads the resource usage of processes from the '/proc' file system (Linux)
+package procstat
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// procDir is the mount point of the proc file system
+const procDir = "/proc"
+
+// clockTicks is the number of clock ticks per second (USER_HZ) used by the CPU times of '/proc/<pid>/stat'. It is 100 in all supported
+// architectures of Linux
+const clockTicks = 100
+
+// Stat is the resource usage of a process (or the sum of a group of processes)
+type Stat struct {
+	CPUTime time.Duration // User and system CPU time
+	RSS     uint64        // Resident set size in bytes
+	Threads int           // Number of threads
+	FDs     int           // Number of open file descriptors. Only counts the processes that the current user can inspect
+}
+
+// Add adds the resource usage of other process
+func (s *Stat) Add(other *Stat) {
+	s.CPUTime += other.CPUTime
+	s.RSS += other.RSS
+	s.Threads += other.Threads
+	s.FDs += other.FDs
+}
+
+// process is the content of '/proc/<pid>/stat' used by this package
+type process struct {
+	parent int
+	stat   Stat
+}
+
+// readProcess reads the '/proc/<pid>/stat' file of a process. Does not count the file descriptors
+func readProcess(pid int) (*process, error) {
+	content, err := os.ReadFile(filepath.Join(procDir, strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return nil, fmt.Errorf("error reading stat file of process %d: %w", pid, err)
+	}
+
+	// The second field is the command name inside parentheses, which can have spaces and parentheses
+	end := strings.LastIndexByte(string(content), ')')
+	if end == -1 {
+		return nil, fmt.Errorf("invalid stat file of process %d", pid)
+	}
+
+	// Fields after the command name. The first one is the third field of the file (state)
+	fields := strings.Fields(string(content[end+1:]))
+	if len(fields) < 22 {
+		return nil, fmt.Errorf("invalid stat file of process %d: expected at least 24 fields", pid)
+	}
+
+	field := func(number int) uint64 {
+		value, _ := strconv.ParseUint(fields[number-3], 10, 64)
+		return value
+	}
+
+	cpuTicks := field(14) + field(15) // utime + stime
+	return &process{
+		parent: int(field(4)),
+		stat: Stat{
+			CPUTime: time.Duration(cpuTicks) * time.Second / clockTicks,
+			RSS:     field(24) * uint64(os.Getpagesize()),
+			Threads: int(field(20)),
+		},
+	}, nil
+}
+
+// countFDs counts the open file descriptors of a process. Returns 0 if the current user can not inspect the process
+func countFDs(pid int) int {
+	entries, err := os.ReadDir(filepath.Join(procDir, strconv.Itoa(pid), "fd"))
+	if err != nil {
+		return 0
+	}
+
+	return len(entries)
+}
+
+// Read reads the resource usage of a process
+func Read(pid int) (*Stat, error) {
+	proc, err := readProcess(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	proc.stat.FDs = countFDs(pid)
+	return &proc.stat, nil
+}
+
+// Snapshot is the state of all processes at a point in time. Used to sum the resource usage of process trees
+type Snapshot struct {
+	processes map[int]*process
+	children  map[int][]int // Children of each process
+}
+
+// NewSnapshot reads the state of all processes
+func NewSnapshot() (*Snapshot, error) {
+	entries, err := os.ReadDir(procDir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading proc directory: %w", err)
+	}
+
+	snapshot := Snapshot{
+		processes: map[int]*process{},
+		children:  map[int][]int{},
+	}
+
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue // Not a process
+		}
+
+		proc, err := readProcess(pid)
+		if err != nil {
+			continue // Ended
+		}
+
+		snapshot.processes[pid] = proc
+		snapshot.children[proc.parent] = append(snapshot.children[proc.parent], pid)
+	}
+
+	return &snapshot, nil
+}
+
+// ReadTree returns the sum of the resource usage of the processes and all their descendants (e.g. the commands started by a shell). The
+// processes that are not in the snapshot are ignored. The file descriptors are counted when calling this method
+func (s *Snapshot) ReadTree(pids ...int) *Stat {
+	total := Stat{}
+
+	visited := map[int]bool{}
+	pending := append([]int{}, pids...)
+	for len(pending) > 0 {
+		pid := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		proc, ok := s.processes[pid]
+		if !ok || visited[pid] {
+			continue
+		}
+		visited[pid] = true
+
+		stat := proc.stat
+		stat.FDs = countFDs(pid)
+		total.Add(&stat)
+		pending = append(pending, s.children[pid]...)
+	}
+
+	return &total
+}
+
+// ReadTree reads the resource usage of the processes and all their descendants and returns their sum (see `Snapshot.ReadTree`)
+func ReadTree(pids ...int) (*Stat, error) {
+	snapshot, err := NewSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot.ReadTree(pids...), nil
+}
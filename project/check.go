@@ -0,0 +1,120 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// CheckReferences returns the problems of the files and projects referenced by the configuration: working directories, shell and Lua files,
+// fallback scripts and tasks, task dependencies and child projects. The child projects are checked recursively
+func (c *Config) CheckReferences() []error {
+	problems := []error{}
+
+	// Child projects. The dependencies of the child projects that can not be read are not checked (the same error)
+	failedChildren := map[string]bool{}
+	folders, err := c.getChildProjects()
+	if err != nil {
+		problems = append(problems, err)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(folders)) {
+		child, err := c.getChildProject(name)
+		if err != nil {
+			problems = append(problems, err)
+			failedChildren[name] = true
+			continue
+		}
+
+		for _, err := range child.CheckReferences() {
+			problems = append(problems, fmt.Errorf("project '%s': %w", name, err))
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(c.Scripts)) {
+		for _, err := range c.Scripts[name].checkReferences() {
+			problems = append(problems, fmt.Errorf("script '%s': %w", name, err))
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(c.Tasks)) {
+		task := c.Tasks[name]
+		for _, err := range task.checkReferences() {
+			problems = append(problems, fmt.Errorf("task '%s': %w", name, err))
+		}
+
+		for _, dep := range task.Deps {
+			depName, _, _ := strings.Cut(dep, ".")
+			if childName, _, ok := strings.Cut(depName, ":"); ok && task.Project == c && failedChildren[childName] {
+				continue
+			}
+
+			_, err := task.Project.GetTaskByName(depName)
+			if err != nil {
+				problems = append(problems, fmt.Errorf("task '%s': dependency '%s': %w", name, dep, err))
+			}
+		}
+	}
+
+	if _, ok := c.Scripts[c.FallbackScript]; c.FallbackScript != "" && !ok {
+		problems = append(problems, fmt.Errorf("fallback script '%s' not found", c.FallbackScript))
+	}
+
+	if _, ok := c.Tasks[c.FallbackTask]; c.FallbackTask != "" && !ok {
+		problems = append(problems, fmt.Errorf("fallback task '%s' not found", c.FallbackTask))
+	}
+
+	return problems
+}
+
+// checkReferences returns the problems of the templates of the script and of the files referenced by it (working directory, shell file
+// and Lua file)
+func (s *Script) checkReferences() []error {
+	problems := []error{}
+
+	if s.templateErr != nil {
+		problems = append(problems, fmt.Errorf("error rendering templates: %w", s.templateErr))
+	}
+
+	err := checkPath(s.Cwd, true)
+	if err != nil {
+		problems = append(problems, fmt.Errorf("working directory: %w", err))
+	}
+
+	if s.File != "" {
+		err := checkPath(s.File, false)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("shell file: %w", err))
+		}
+	}
+
+	if s.LuaFile != "" {
+		err := checkPath(s.LuaFile, false)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("lua file: %w", err))
+		}
+	}
+
+	return problems
+}
+
+// checkPath returns an error if the path does not exist or is not of the expected type (directory or file)
+func checkPath(path string, isDir bool) error {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("'%s' does not exist", path)
+	} else if err != nil {
+		return fmt.Errorf("error checking '%s': %w", path, err)
+	}
+
+	if isDir && !info.IsDir() {
+		return fmt.Errorf("'%s' is not a directory", path)
+	} else if !isDir && info.IsDir() {
+		return fmt.Errorf("'%s' is a directory", path)
+	}
+
+	return nil
+}
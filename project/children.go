@@ -0,0 +1,167 @@
+package project
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/LucasAVasco/falcula/fingerprint"
+)
+
+// isProjectGlob returns true if the path of a child project is a glob (e.g. `packages/*`)
+func isProjectGlob(projectPath string) bool {
+	return strings.ContainsAny(projectPath, `*?[`)
+}
+
+// getChildProjects returns the folders of the child projects, by name. The globs are expanded to every matching directory with a
+// configuration file, named by the `name` field of its configuration or by the directory name. The result is cached
+func (c *Config) getChildProjects() (map[string]string, error) {
+	c.childrenMutex.Lock()
+	defer c.childrenMutex.Unlock()
+
+	if c.childFolders != nil {
+		return c.childFolders, nil
+	}
+
+	folders := map[string]string{}
+	origins := map[string]string{} // Entry of the `projects` section that registered each child project
+
+	add := func(name, folder, entry string) error {
+		if origin, ok := origins[name]; ok {
+			return fmt.Errorf("child project '%s' is registered by both '%s' and '%s'", name, origin, entry)
+		}
+		folders[name] = folder
+		origins[name] = entry
+		return nil
+	}
+
+	for _, entry := range slices.Sorted(maps.Keys(c.Projects)) {
+		projectPath := c.Projects[entry]
+
+		if !isProjectGlob(projectPath) {
+			if !filepath.IsAbs(projectPath) {
+				projectPath = filepath.Join(c.Folder, projectPath)
+			}
+
+			err := add(entry, projectPath, entry)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// Every directory that matches the glob and has a configuration file
+		dir := c.Folder
+		pattern := projectPath
+		if filepath.IsAbs(pattern) {
+			dir = "/"
+			pattern = strings.TrimPrefix(pattern, "/")
+		}
+
+		files, err := fingerprint.Glob(dir, []string{path.Join(filepath.ToSlash(pattern), ProjectFileName)})
+		if err != nil {
+			return nil, fmt.Errorf("error expanding child projects '%s': %w", entry, err)
+		}
+
+		for _, file := range files {
+			if path.Base(file) != ProjectFileName {
+				continue
+			}
+
+			folder := filepath.Join(dir, filepath.Dir(filepath.FromSlash(file)))
+			child, err := c.readChildProject(folder)
+			if err != nil {
+				return nil, err
+			}
+
+			name := child.Name
+			if name == "" {
+				name = filepath.Base(folder)
+			}
+
+			err = add(name, folder, entry)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	c.childFolders = folders
+	return folders, nil
+}
+
+// readChildProject reads the configuration of the child project in the provided folder. The configurations are cached, so each one is
+// only read once. Must be called with the children mutex locked
+func (c *Config) readChildProject(folder string) (*Config, error) {
+	if child, ok := c.childConfigs[folder]; ok {
+		return child, nil
+	}
+
+	path := filepath.Join(folder, ProjectFileName)
+	child, err := readConfigFile(path, c.resolvedVars, false)
+	if err != nil {
+		return nil, fmt.Errorf("error reading project file '%s': %w", path, err) // The decoding errors only have the line and column
+	}
+
+	if c.childConfigs == nil {
+		c.childConfigs = map[string]*Config{}
+	}
+	c.childConfigs[folder] = child
+
+	return child, nil
+}
+
+// getChildProject returns the configuration of the child project with the provided name (not recursive)
+func (c *Config) getChildProject(name string) (*Config, error) {
+	folders, err := c.getChildProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	folder, ok := folders[name]
+	if !ok {
+		return nil, fmt.Errorf("project '%s' not found", name)
+	}
+
+	c.childrenMutex.Lock()
+	defer c.childrenMutex.Unlock()
+
+	child, err := c.readChildProject(folder)
+	if err != nil {
+		return nil, fmt.Errorf("error reading child project '%s': %w", name, err)
+	}
+
+	return child, nil
+}
+
+// GetAllProjects returns the child projects of the project and their child projects (recursively), by name. The names of the inner
+// projects use the `project:innerProject` syntax
+func (c *Config) GetAllProjects() (map[string]*Config, error) {
+	folders, err := c.getChildProjects()
+	if err != nil {
+		return nil, fmt.Errorf("error getting child projects: %w", err)
+	}
+
+	projects := map[string]*Config{}
+	for name := range folders {
+		child, err := c.getChildProject(name)
+		if err != nil {
+			return nil, err
+		}
+		projects[name] = child
+
+		innerProjects, err := child.GetAllProjects()
+		if err != nil {
+			return nil, fmt.Errorf("error getting child projects of project '%s': %w", name, err)
+		}
+
+		for innerName, innerProject := range innerProjects {
+			projects[name+":"+innerName] = innerProject
+		}
+	}
+
+	return projects, nil
+}
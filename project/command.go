@@ -2,40 +2,98 @@ package project
 
 import (
 	"fmt"
-
-	"github.com/goccy/go-yaml"
+	"maps"
+	"slices"
+	"strings"
 )
 
+// defaultPlatformKey is the key of the command of the platforms without a specific command (see `Command.Platforms`)
+const defaultPlatformKey = "default"
+
 // Command is a command to be executed. It can be either a single string (executed by the shell) or a list of strings specifying the command
-// to be executed and its arguments (will not be executed by the shell)
+// to be executed and its arguments (will not be executed by the shell).
+//
+// It can also be a mapping with a command per platform (see `Platforms`), resolved to the command of the current platform when the
+// configuration is read
 type Command struct {
 	String string
 	List   []string
+
+	// Commands by platform: 'os/arch' (e.g. 'linux/amd64'), 'os' (e.g. 'linux') or 'default'. Nil after resolving the command of the current
+	// platform (see `resolvePlatform`), so only has value if there is no command for the current platform
+	Platforms map[string]*Command
 }
 
-func (s *Command) UnmarshalYAML(data []byte) error {
+func (s *Command) UnmarshalYAML(unmarshal func(any) error) error {
 	var single string
 
 	// Try parsing as a single string first
-	if err := yaml.Unmarshal(data, &single); err == nil {
+	if err := unmarshal(&single); err == nil {
 		s.String = single
 		return nil
 	}
 
-	// Fallback to parsing as a slice of strings
+	// Then as a slice of strings
 	var list []string
-	if err := yaml.Unmarshal(data, &list); err != nil {
-		return fmt.Errorf("error parsing command: %w", err)
+	if err := unmarshal(&list); err == nil {
+		s.List = list
+		return nil
+	}
+
+	// Fallback to parsing as a command per platform (keeps the position of the errors)
+	platforms := map[string]*Command{}
+	if err := unmarshal(&platforms); err != nil {
+		return err
 	}
-	s.List = list
+
+	for platform, command := range platforms {
+		if command == nil || command.Platforms != nil {
+			return fmt.Errorf("the command of platform '%s' must be a string or a list of strings", platform)
+		}
+	}
+	s.Platforms = platforms
 
 	return nil
 }
 
+// MarshalYAML writes the command in the same format it was read (a single string, a list of strings or a command per platform)
+func (s Command) MarshalYAML() (any, error) {
+	if s.Platforms != nil {
+		return s.Platforms, nil
+	}
+
+	if s.List != nil {
+		return s.List, nil
+	}
+
+	return s.String, nil
+}
+
 // IsNotEmpty returns true if the command has something to execute (either a shell command or a executable with arguments)
 func (s *Command) IsNotEmpty() bool {
-	if s.String == "" && len(s.List) == 0 {
+	if s.String == "" && len(s.List) == 0 && len(s.Platforms) == 0 {
 		return false
 	}
 	return true
 }
+
+// resolvePlatform replaces a command per platform by the command of the provided platform ('os/arch'). The most specific key wins: 'os/arch',
+// then 'os', then 'default'. Keeps the commands per platform if none of them applies
+func (s *Command) resolvePlatform(platform string) {
+	if s.Platforms == nil {
+		return
+	}
+
+	goos, _, _ := strings.Cut(platform, "/")
+	for _, key := range []string{platform, goos, defaultPlatformKey} {
+		if command, ok := s.Platforms[key]; ok {
+			*s = *command
+			return
+		}
+	}
+}
+
+// getPlatformKeys returns the sorted platforms of a command per platform
+func (s *Command) getPlatformKeys() []string {
+	return slices.Sorted(maps.Keys(s.Platforms))
+}
@@ -4,9 +4,9 @@ package project
 import (
 	"fmt"
 	"maps"
-	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/goccy/go-yaml"
 )
@@ -16,33 +16,95 @@ const ProjectFileName = "falcula.yaml"
 // Config is the project configuration
 type Config struct {
 	Folder         string             `yaml:"-"`
-	Projects       map[string]string  `yaml:"projects"`
+	Name           string             `yaml:"name"`     // Name of the project when registered by a glob of the parent project (see `Projects`)
+	Projects       map[string]string  `yaml:"projects"` // Child project folders by name. A glob (e.g. `packages/*`) registers all matching projects
 	Root           bool               `yaml:"root"`
 	Scripts        map[string]*Script `yaml:"scripts"`
 	FallbackScript string             `yaml:"fallback_script"`
 	Tasks          map[string]*Task   `yaml:"tasks"`
 	FallbackTask   string             `yaml:"fallback_task"`
+	Log            LogConfig          `yaml:"log"`
+	Resources      ResourcesConfig    `yaml:"resources"`
+	Vars           map[string]*Var    `yaml:"vars"` // Variables of the templates. Overrides the variables of the parent project
+	Env            map[string]string  `yaml:"env"`  // Environment variables of the commands of all scripts and tasks
+
+	resolvedVars   map[string]string // Variables of the project and its parents
+	origins        map[string]string // Files that defined the values that are not from the project file, by YAML path (see `readConfigLayers`)
+	userConfigFile string            // User configuration file merged under the project file (see `GetUserConfigFile`). Empty if not merged
+
+	childrenMutex sync.Mutex
+	childFolders  map[string]string  // Cache of `getChildProjects`
+	childConfigs  map[string]*Config // Cache of the child project configurations, by folder
 }
 
-// ReadConfigFile reads the project configuration file and parses it
+// ReadConfigFile reads the project configuration file and parses it. The local configuration file of the project (see `LocalFileName`)
+// is merged over it
 func ReadConfigFile(path string) (*Config, error) {
+	return readConfigFile(path, nil, false)
+}
+
+// readConfigFile reads the configuration file of a project (merged with its local configuration file and optionally over the user
+// configuration file). The variables of the parent project are available to its templates
+func readConfigFile(path string, parentVars map[string]string, withUserConfig bool) (*Config, error) {
 	c := Config{
 		Projects: make(map[string]string),
 		Scripts:  make(map[string]*Script),
 	}
 
-	fileContent, err := os.ReadFile(path)
+	layers := []configLayer{}
+	if withUserConfig {
+		userConfig, err := GetUserConfigFile()
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, configLayer{path: userConfig, optional: true})
+		c.userConfigFile = userConfig
+	}
+	layers = append(layers, configLayer{path: path}, configLayer{path: filepath.Join(filepath.Dir(path), LocalFileName), optional: true})
+
+	fileContent, origins, err := readConfigLayers(path, layers)
 	if err != nil {
-		return nil, fmt.Errorf("error reading configuration file: %w", err)
+		return nil, err
 	}
 
-	err = yaml.Unmarshal(fileContent, &c)
+	err = decodeConfig(fileContent, &c)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing configuration file: %w", err)
 	}
 
+	c.origins = origins
+
+	// The variables of the user configuration do not override the variables of the parent projects
+	for name := range c.Vars {
+		if _, ok := parentVars[name]; ok && c.isFromUserConfig(getYAMLPath("$.vars", name)) {
+			delete(c.Vars, name)
+		}
+	}
 	c.Folder = filepath.Dir(path)
 
+	// Variables and templates
+	err = c.resolveVars(parentVars)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving variables: %w", err)
+	}
+
+	data := &templateData{Vars: c.resolvedVars, Folder: c.Folder}
+	for name, value := range c.Env {
+		c.Env[name], err = renderTemplate(value, data)
+		if err != nil {
+			return nil, fmt.Errorf("error rendering environment variable '%s': %w", name, err)
+		}
+	}
+
+	// The template errors of a script or task only fail it (see `Script.CheckTemplates`)
+	for _, script := range c.Scripts {
+		script.render(data)
+	}
+
+	for _, task := range c.Tasks {
+		task.render(data)
+	}
+
 	// Configuring scripts
 	for _, script := range c.Scripts {
 		script.Project = &c
@@ -61,6 +123,12 @@ func ReadConfigFile(path string) (*Config, error) {
 		}
 	}
 
+	// Configuring logs
+	err = c.Log.ConvertToAbsPath(c.Folder)
+	if err != nil {
+		return nil, fmt.Errorf("error configuring logs: %w", err)
+	}
+
 	// Validates the scripts
 	for name, script := range c.Scripts {
 		err = script.Validate()
@@ -77,26 +145,64 @@ func ReadConfigFile(path string) (*Config, error) {
 		}
 	}
 
+	// Validates the resource usage sampling
+	_, err = c.Resources.GetSampleInterval()
+	if err != nil {
+		return nil, fmt.Errorf("error validating resources configuration: %w", err)
+	}
+
 	return &c, nil
 }
 
-// GetChildProjectByName returns the child project with the given name or nil if not found
-func (c *Config) GetChildProjectByName(name string) (*Config, error) {
-	subProjectName, innerName, hasSubProjectName := strings.Cut(name, ":")
+// decodeConfig decodes a configuration file. Unknown fields (e.g. typos) are errors with their line and column
+func decodeConfig(data []byte, c *Config) error {
+	return yaml.UnmarshalWithOptions(data, c, yaml.Strict())
+}
 
-	// Gets first inner project
-	projectPath, ok := c.Projects[subProjectName]
-	if !ok {
-		return nil, fmt.Errorf("project '%s' not found", subProjectName)
+// isFromUserConfig returns true if the value with the provided YAML path (e.g. `$.scripts.dev`) is defined by the user configuration file
+func (c *Config) isFromUserConfig(path string) bool {
+	return c.userConfigFile != "" && c.origins[path] == c.userConfigFile
+}
+
+// Render returns the configuration as YAML, with the resolved variables and templates and the absolute paths. The values that are not
+// from the project file have a comment with their origin (e.g. `# from falcula.local.yaml`)
+func (c *Config) Render() ([]byte, error) {
+	// Shows the files that defined the values that are not from the project file
+	comments := yaml.CommentMap{}
+	for path, origin := range c.origins {
+		if relative, err := filepath.Rel(c.Folder, origin); err == nil && origin != c.userConfigFile {
+			origin = relative
+		}
+		comments[path] = []*yaml.Comment{yaml.LineComment(" from " + origin)}
 	}
 
-	if !filepath.IsAbs(projectPath) {
-		projectPath = filepath.Join(c.Folder, projectPath)
+	data, err := yaml.MarshalWithOptions(c, yaml.OmitEmpty(), yaml.WithComment(comments))
+	if err != nil {
+		return nil, fmt.Errorf("error encoding configuration: %w", err)
+	}
+
+	return data, nil
+}
+
+// GetScriptOrigin returns the configuration file that defined the script with the provided name (not recursive): the project file, its
+// local configuration file or the user configuration file. The values of a script overridden by other files are not considered
+func (c *Config) GetScriptOrigin(name string) string {
+	origin, ok := c.origins[getYAMLPath("$.scripts", name)]
+	if !ok {
+		return filepath.Join(c.Folder, ProjectFileName)
 	}
 
-	project, err := ReadConfigFile(projectPath + "/" + ProjectFileName)
+	return origin
+}
+
+// GetChildProjectByName returns the child project with the given name or nil if not found
+func (c *Config) GetChildProjectByName(name string) (*Config, error) {
+	subProjectName, innerName, hasSubProjectName := strings.Cut(name, ":")
+
+	// Gets first inner project
+	project, err := c.getChildProject(subProjectName)
 	if err != nil {
-		return nil, fmt.Errorf("error reading child project configuration file '%s': %w", innerName, err)
+		return nil, err
 	}
 	if !hasSubProjectName {
 		return project, nil
@@ -189,10 +295,15 @@ func (c *Config) GetAllScripts() (map[string]*Script, error) {
 	maps.Copy(scripts, c.Scripts)
 
 	// Adds scripts from children projects
-	for projectName, projectPath := range c.Projects {
-		project, err := ReadConfigFile(projectPath + "/" + ProjectFileName)
+	projects, err := c.getChildProjects()
+	if err != nil {
+		return nil, fmt.Errorf("error getting child projects: %w", err)
+	}
+
+	for projectName := range projects {
+		project, err := c.getChildProject(projectName)
 		if err != nil {
-			return nil, fmt.Errorf("error reading configuration file of project '%s' at path '%s': %v", projectName, projectPath, err)
+			return nil, err
 		}
 		subScripts, err := project.GetAllScripts()
 		if err != nil {
@@ -258,10 +369,15 @@ func (c *Config) GetAllTasks() (map[string]*Task, error) {
 	maps.Copy(tasks, c.Tasks)
 
 	// Adds tasks from children projects
-	for projectName, projectPath := range c.Projects {
-		project, err := ReadConfigFile(projectPath + "/" + ProjectFileName)
+	projects, err := c.getChildProjects()
+	if err != nil {
+		return nil, fmt.Errorf("error getting child projects: %w", err)
+	}
+
+	for projectName := range projects {
+		project, err := c.getChildProject(projectName)
 		if err != nil {
-			return nil, fmt.Errorf("error reading configuration file of project '%s' at path '%s': %v", projectName, projectPath, err)
+			return nil, err
 		}
 
 		subTasks, err := project.GetAllTasks()
@@ -0,0 +1,77 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetScriptOrigin(t *testing.T) {
+	dir := t.TempDir()
+	configHome := t.TempDir()
+	for _, name := range []string{"XDG_CONFIG_HOME", "HOME", "AppData"} { // User configuration directory of each platform
+		t.Setenv(name, configHome)
+	}
+
+	projectFile := filepath.Join(dir, ProjectFileName)
+	localFile := filepath.Join(dir, LocalFileName)
+	userFile, err := GetUserConfigFile()
+	if err != nil {
+		t.Fatalf("error getting user configuration file: %v", err)
+	}
+
+	writeFile(t, projectFile, `
+scripts:
+  build:
+    command: make
+  test:
+    command: make test
+`)
+	writeFile(t, localFile, `
+scripts:
+  test:
+    command: make test-local # Only overrides the command
+  dev:
+    command: make dev
+`)
+	writeFile(t, userFile, `
+scripts:
+  lint:
+    command: golangci-lint run
+`)
+
+	config, err := readConfigFile(projectFile, nil, true)
+	if err != nil {
+		t.Fatalf("error reading configuration: %v", err)
+	}
+
+	tests := map[string]string{
+		"build": projectFile,
+		"test":  projectFile,
+		"dev":   localFile,
+		"lint":  userFile,
+	}
+
+	for name, want := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := config.GetScriptOrigin(name); got != want {
+				t.Errorf("got origin %q, expected %q", got, want)
+			}
+		})
+	}
+}
+
+// writeFile writes a file and its parent directories
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		t.Fatalf("error creating directory: %v", err)
+	}
+
+	err = os.WriteFile(path, []byte(content), 0644)
+	if err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+}
@@ -0,0 +1,195 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/goccy/go-yaml"
+)
+
+// FindConfig finds and reads the configuration of the project of the provided directory: the nearest `falcula.yaml` file in the
+// directory or in its parents. The search continues upwards until a configuration with `root: true` (or the file system root), and the
+// scripts, tasks, variables and environment variables of the ancestor configurations are merged into the nearest one (the nearest
+// definition wins)
+func FindConfig(dir string) (*Config, error) {
+	paths, err := findConfigFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reads from the outermost configuration, so the variables of each configuration are available to the nearer ones
+	configs := []*Config{}
+	var parentVars map[string]string
+	for i, path := range slices.Backward(paths) {
+		config, err := readConfigFile(path, parentVars, i == 0) // Only the nearest configuration is merged over the user configuration
+		if err != nil {
+			return nil, fmt.Errorf("error reading configuration file '%s': %w", path, err)
+		}
+
+		configs = append(configs, config)
+		parentVars = config.resolvedVars
+	}
+
+	nearest := configs[len(configs)-1]
+	nearest.mergeAncestors(configs[:len(configs)-1])
+
+	return nearest, nil
+}
+
+// findConfigFiles returns the paths of the configuration files from the provided directory upwards, nearest first. Stops at the first
+// configuration with `root: true`
+func findConfigFiles(dir string) ([]string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error getting absolute path of directory '%s': %w", dir, err)
+	}
+
+	paths := []string{}
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+		isRoot, err := isRootConfigFile(path)
+		if err == nil {
+			paths = append(paths, path)
+			if isRoot {
+				break
+			}
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("project file '%s' not found", ProjectFileName)
+	}
+
+	return paths, nil
+}
+
+// isRootConfigFile returns true if the configuration file has `root: true`. Returns an error wrapping `os.ErrNotExist` if the file does not
+// exist
+func isRootConfigFile(path string) (bool, error) {
+	fileContent, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("error reading configuration file '%s': %w", path, err)
+	}
+
+	config := struct {
+		Root bool `yaml:"root"`
+	}{}
+	err = yaml.Unmarshal(fileContent, &config)
+	if err != nil {
+		return false, fmt.Errorf("error parsing configuration file '%s': %w", path, err)
+	}
+
+	return config.Root, nil
+}
+
+// mergeAncestors merges the scripts, tasks, fallbacks, variables and environment variables of the ancestor configurations (outermost
+// first) that are not defined in the configuration (the definitions of the user configuration are overridden). The merged scripts and
+// tasks keep their project (and so their working directory and child projects). The ancestor configurations are not changed
+func (c *Config) mergeAncestors(ancestors []*Config) {
+	if c.Env == nil {
+		c.Env = map[string]string{}
+	}
+	if c.Vars == nil {
+		c.Vars = map[string]*Var{}
+	}
+
+	for _, ancestor := range slices.Backward(ancestors) {
+		// Origin of the inherited values (see `Config.Render`)
+		inherit := func(section, name string) {
+			path := getYAMLPath(getYAMLPath("$", section), name)
+			origin, ok := ancestor.origins[path]
+			if !ok {
+				origin = filepath.Join(ancestor.Folder, ProjectFileName)
+			}
+			c.origins[path] = origin
+		}
+
+		for name, script := range ancestor.Scripts {
+			if _, ok := c.Scripts[name]; !ok || c.isFromUserConfig(getYAMLPath("$.scripts", name)) {
+				c.Scripts[name] = script
+				inherit("scripts", name)
+			}
+		}
+
+		if c.Tasks == nil {
+			c.Tasks = map[string]*Task{}
+		}
+		for name, task := range ancestor.Tasks {
+			if _, ok := c.Tasks[name]; !ok || c.isFromUserConfig(getYAMLPath("$.tasks", name)) {
+				c.Tasks[name] = task
+				inherit("tasks", name)
+			}
+		}
+
+		for name, value := range ancestor.Env {
+			if _, ok := c.Env[name]; !ok || c.isFromUserConfig(getYAMLPath("$.env", name)) {
+				c.Env[name] = value
+				inherit("env", name)
+			}
+		}
+
+		if (c.FallbackScript == "" || c.isFromUserConfig("$.fallback_script")) && ancestor.FallbackScript != "" {
+			c.FallbackScript = ancestor.FallbackScript
+			c.origins["$.fallback_script"] = filepath.Join(ancestor.Folder, ProjectFileName)
+		}
+
+		if (c.FallbackTask == "" || c.isFromUserConfig("$.fallback_task")) && ancestor.FallbackTask != "" {
+			c.FallbackTask = ancestor.FallbackTask
+			c.origins["$.fallback_task"] = filepath.Join(ancestor.Folder, ProjectFileName)
+		}
+	}
+
+	// The variables are already inherited when reading the configurations (see `FindConfig`)
+	for name, value := range c.resolvedVars {
+		if _, ok := c.Vars[name]; ok {
+			continue
+		}
+
+		c.Vars[name] = &Var{Value: value, resolved: value}
+		for _, ancestor := range slices.Backward(ancestors) {
+			if ancestorVar, ok := ancestor.Vars[name]; ok {
+				c.Vars[name] = ancestorVar // Keeps the definition (see `Var.MarshalYAML`)
+				c.origins[getYAMLPath("$.vars", name)] = filepath.Join(ancestor.Folder, ProjectFileName)
+				break
+			}
+		}
+	}
+
+	// The merged scripts and tasks use the environment variables of the nearest configuration. They are copied, so the scripts and tasks
+	// of the ancestor configurations keep their environment variables
+	for name, script := range c.Scripts {
+		if script.Project != c {
+			inherited := *script
+			inherited.Env = getInheritedEnv(c.Env, script.Env)
+			c.Scripts[name] = &inherited
+		}
+	}
+
+	for name, task := range c.Tasks {
+		if task.Project != c {
+			inherited := *task
+			inherited.Env = getInheritedEnv(c.Env, task.Env)
+			c.Tasks[name] = &inherited
+		}
+	}
+}
+
+// getInheritedEnv returns the environment variables of a merged script or task: the variables of the nearest configuration overridden by
+// the variables of the script or task
+func getInheritedEnv(configEnv, scriptEnv map[string]string) map[string]string {
+	env := maps.Clone(configEnv)
+	maps.Copy(env, scriptEnv)
+	return env
+}
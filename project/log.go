@@ -0,0 +1,69 @@
+package project
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/LucasAVasco/falcula/logfile"
+	"github.com/LucasAVasco/falcula/logsink"
+	"github.com/LucasAVasco/falcula/redact"
+	"github.com/LucasAVasco/falcula/units"
+)
+
+// LogConfig is the configuration of the service logs of the project
+type LogConfig struct {
+	Sinks        []*logsink.Config `yaml:"sinks"`         // Destinations of the service logs besides the session log file
+	Dir          string            `yaml:"dir"`           // Directory of the session log files. Uses a temporary directory if empty
+	Keep         bool              `yaml:"keep"`          // Keeps the session log file after exiting
+	KeepSessions int               `yaml:"keep_sessions"` // Maximum number of sessions kept in the log directory
+	MaxSize      string            `yaml:"max_size"`      // Maximum size of all sessions in the log directory (e.g. '100M')
+	Compress     bool              `yaml:"compress"`      // Compresses the old sessions with gzip
+	Redact       redact.Config     `yaml:"redact"`        // Secrets masked in all logs
+}
+
+// GetRetentionPolicy returns the retention policy of the log directory. Returns nil if there is no policy
+func (l *LogConfig) GetRetentionPolicy() (*logfile.RetentionPolicy, error) {
+	if l.KeepSessions <= 0 && l.MaxSize == "" && !l.Compress {
+		return nil, nil
+	}
+
+	policy := logfile.RetentionPolicy{
+		KeepSessions: l.KeepSessions,
+		Compress:     l.Compress,
+	}
+
+	if l.MaxSize != "" {
+		var err error
+		policy.MaxSize, err = units.ParseBytes(l.MaxSize)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing maximum size of the log directory: %w", err)
+		}
+	}
+
+	return &policy, nil
+}
+
+// ConvertToAbsPath converts the paths of the log directory and sinks to absolute paths
+func (l *LogConfig) ConvertToAbsPath(folder string) error {
+	if l.Dir != "" && !filepath.IsAbs(l.Dir) {
+		var err error
+		l.Dir, err = filepath.Abs(filepath.Join(folder, l.Dir))
+		if err != nil {
+			return fmt.Errorf("error getting absolute path of log directory: %w", err)
+		}
+	}
+
+	for _, sink := range l.Sinks {
+		if sink.Path == "" || filepath.IsAbs(sink.Path) {
+			continue
+		}
+
+		var err error
+		sink.Path, err = filepath.Abs(filepath.Join(folder, sink.Path))
+		if err != nil {
+			return fmt.Errorf("error getting absolute path of log sink: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// LocalFileName is the name of the personal configuration file of a project (should be ignored by the version control). It is merged over
+// the `falcula.yaml` file of the same folder
+const LocalFileName = "falcula.local.yaml"
+
+// GetUserConfigFile returns the path of the user configuration file (`$XDG_CONFIG_HOME/falcula/config.yaml` on Linux). It is merged under
+// the project configuration, so the project overrides it
+func GetUserConfigFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("error getting user configuration directory: %w", err)
+	}
+
+	return filepath.Join(dir, "falcula", "config.yaml"), nil
+}
+
+// configLayer is a configuration file merged with other configuration files
+type configLayer struct {
+	path     string
+	optional bool // Ignored if it does not exist
+}
+
+// readConfigLayers reads and deep merges the configuration files (each one overrides the previous ones). The mappings are merged key by
+// key, the other values (including lists) are replaced and a `null` value removes the key. Returns the merged configuration as YAML and
+// the file that defined each merged value that is not from the main file (by YAML path, e.g. `$.scripts.dev.command`)
+func readConfigLayers(mainFile string, layers []configLayer) ([]byte, map[string]string, error) {
+	contents := [][]byte{}
+	paths := []string{}
+
+	for _, layer := range layers {
+		fileContent, err := os.ReadFile(layer.path)
+		if err != nil {
+			if layer.optional && errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, nil, fmt.Errorf("error reading configuration file: %w", err)
+		}
+
+		contents = append(contents, fileContent)
+		paths = append(paths, layer.path)
+	}
+
+	// Keeps the file content (and so the error positions) when there is nothing to merge
+	if len(contents) == 1 && paths[0] == mainFile {
+		return contents[0], map[string]string{}, nil
+	}
+
+	merged := map[string]any{}
+	origins := map[string]string{}
+	for i, fileContent := range contents {
+		// Decodes as a configuration first, so the errors have the position in this file
+		err := decodeConfig(fileContent, &Config{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing configuration file '%s': %w", paths[i], err)
+		}
+
+		values := map[string]any{}
+		err = yaml.Unmarshal(fileContent, &values)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing configuration file '%s': %w", paths[i], err)
+		}
+
+		origin := paths[i]
+		if origin == mainFile {
+			origin = ""
+		}
+		mergeConfigValues(merged, values, "$", origin, origins)
+	}
+
+	data, err := yaml.Marshal(merged)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error encoding merged configuration: %w", err)
+	}
+
+	return data, origins, nil
+}
+
+// mergeConfigValues merges the source values into the destination values (see `readConfigLayers`). Saves the origin of the merged values
+// in the origins map (an empty origin removes it)
+func mergeConfigValues(dst, src map[string]any, path string, origin string, origins map[string]string) {
+	for key, value := range src {
+		keyPath := getYAMLPath(path, key)
+
+		srcMap, srcIsMap := value.(map[string]any)
+		dstMap, dstIsMap := dst[key].(map[string]any)
+		if srcIsMap && dstIsMap {
+			// The origin of the mapping becomes the origin of its current values
+			if mapOrigin, ok := origins[keyPath]; ok {
+				delete(origins, keyPath)
+				for innerKey := range dstMap {
+					origins[getYAMLPath(keyPath, innerKey)] = mapOrigin
+				}
+			}
+
+			mergeConfigValues(dstMap, srcMap, keyPath, origin, origins)
+			continue
+		}
+
+		// Replaces the value (and the origin of its inner values)
+		for p := range origins {
+			if p == keyPath || strings.HasPrefix(p, keyPath+".") {
+				delete(origins, p)
+			}
+		}
+
+		if value == nil {
+			delete(dst, key)
+		} else {
+			dst[key] = value
+		}
+
+		if origin != "" {
+			origins[keyPath] = origin
+		}
+	}
+}
+
+// simpleYAMLKeyRegex matches the keys that do not need to be quoted in a YAML path
+var simpleYAMLKeyRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// getYAMLPath returns the YAML path of a key of the mapping with the provided path
+func getYAMLPath(path string, key string) string {
+	if simpleYAMLKeyRegex.MatchString(key) {
+		return path + "." + key
+	}
+
+	return path + ".'" + strings.ReplaceAll(key, "'", `\'`) + "'"
+}
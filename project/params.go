@@ -0,0 +1,235 @@
+package project
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Types of the parameters
+const (
+	ParamString = "string"
+	ParamInt    = "int"
+	ParamBool   = "bool"
+	ParamEnum   = "enum"
+	ParamPath   = "path" // Converted to an absolute path (relative to the invoke directory)
+)
+
+// paramEnvPrefix is the prefix of the environment variables with the parameter values
+const paramEnvPrefix = "FALCULA_PARAM_"
+
+// paramNameRegex matches the valid parameter names
+var paramNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*$`)
+
+// Param is a typed parameter of a script or task. The user provides it with `--name=value` (or `--name value`) after the script or task
+// name. The boolean parameters can also be provided as `--name`
+type Param struct {
+	Name        string   `yaml:"name"`
+	Type        string   `yaml:"type"` // Default: string
+	Default     any      `yaml:"default"`
+	Required    bool     `yaml:"required"`
+	Description string   `yaml:"description"`
+	Values      []string `yaml:"values"` // Allowed values of an enum parameter
+}
+
+// Params are the parameters of a script or task
+type Params []*Param
+
+// ParamValues are the parsed values of the parameters, by name. The values are strings, except the int (`int`) and bool (`bool`)
+// parameters. The parameters that were not provided and have no default value are absent, except the bool parameters (false)
+type ParamValues map[string]any
+
+// Validate returns an error if a parameter is not valid
+func (p Params) Validate() error {
+	names := []string{}
+
+	for _, param := range p {
+		if !paramNameRegex.MatchString(param.Name) {
+			return fmt.Errorf("invalid parameter name '%s', must start with a letter and only contain letters, digits, '_' and '-'",
+				param.Name)
+		}
+
+		if slices.Contains(names, param.Name) {
+			return fmt.Errorf("duplicated parameter '%s'", param.Name)
+		}
+		names = append(names, param.Name)
+
+		switch param.Type {
+		case "":
+			param.Type = ParamString
+		case ParamString, ParamInt, ParamBool, ParamPath:
+		case ParamEnum:
+			if len(param.Values) == 0 {
+				return fmt.Errorf("the enum parameter '%s' has no values", param.Name)
+			}
+		default:
+			return fmt.Errorf("invalid type '%s' of parameter '%s', must be '%s', '%s', '%s', '%s' or '%s'", param.Type, param.Name,
+				ParamString, ParamInt, ParamBool, ParamEnum, ParamPath)
+		}
+
+		if param.Default != nil {
+			_, err := param.parse(fmt.Sprint(param.Default), "")
+			if err != nil {
+				return fmt.Errorf("invalid default value of parameter '%s': %w", param.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// get returns the parameter with the provided name. Returns nil if not found
+func (p Params) get(name string) *Param {
+	for _, param := range p {
+		if param.Name == name {
+			return param
+		}
+	}
+
+	return nil
+}
+
+// Parse parses the parameters provided as flags (`--name=value`). Returns the parameter values and the remaining (positional) arguments.
+// The arguments after `--` are always positional. The paths are relative to the provided directory
+func (p Params) Parse(args []string, dir string) (ParamValues, []string, error) {
+	values := ParamValues{}
+	positional := []string{}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+
+		flag, ok := strings.CutPrefix(arg, "--")
+		if !ok {
+			positional = append(positional, arg)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(flag, "=")
+		param := p.get(name)
+		if param == nil {
+			return nil, nil, fmt.Errorf("unknown parameter '--%s'", name)
+		}
+
+		if !hasValue {
+			if param.Type == ParamBool {
+				value = "true"
+			} else if i+1 < len(args) {
+				i++
+				value = args[i]
+			} else {
+				return nil, nil, fmt.Errorf("parameter '--%s' requires a value", name)
+			}
+		}
+
+		parsed, err := param.parse(value, dir)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid value of parameter '--%s': %w", name, err)
+		}
+		values[name] = parsed
+	}
+
+	// Default values
+	for _, param := range p {
+		if _, ok := values[param.Name]; ok {
+			continue
+		}
+
+		switch {
+		case param.Default != nil:
+			values[param.Name], _ = param.parse(fmt.Sprint(param.Default), dir) // Validated by `Validate`
+		case param.Required:
+			return nil, nil, fmt.Errorf("missing required parameter '--%s'", param.Name)
+		case param.Type == ParamBool:
+			values[param.Name] = false
+		}
+	}
+
+	return values, positional, nil
+}
+
+// parse parses the value of the parameter according to its type
+func (p *Param) parse(value string, dir string) (any, error) {
+	switch p.Type {
+	case ParamInt:
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not an integer", value)
+		}
+		return number, nil
+
+	case ParamBool:
+		boolean, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a boolean", value)
+		}
+		return boolean, nil
+
+	case ParamEnum:
+		if !slices.Contains(p.Values, value) {
+			return nil, fmt.Errorf("'%s' is not one of: %s", value, strings.Join(p.Values, ", "))
+		}
+		return value, nil
+
+	case ParamPath:
+		if value == "" || filepath.IsAbs(value) {
+			return value, nil
+		}
+		return filepath.Join(dir, value), nil
+
+	default:
+		return value, nil
+	}
+}
+
+// GetEnv returns the parameter values as environment variables (e.g. `FALCULA_PARAM_OUTPUT_DIR=/tmp/out` for the 'output-dir' parameter)
+func (p Params) GetEnv(values ParamValues) []string {
+	env := []string{}
+	for _, param := range p {
+		if value, ok := values[param.Name]; ok {
+			name := strings.ToUpper(strings.ReplaceAll(param.Name, "-", "_"))
+			env = append(env, paramEnvPrefix+name+"="+fmt.Sprint(value))
+		}
+	}
+
+	return env
+}
+
+// WriteHelp writes the description of the parameters (one per line)
+func (p Params) WriteHelp(w io.Writer) error {
+	writer := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+
+	for _, param := range p {
+		usage := "--" + param.Name
+		switch param.Type {
+		case ParamBool:
+		case ParamEnum:
+			usage += "=<" + strings.Join(param.Values, "|") + ">"
+		default:
+			usage += "=<" + param.Type + ">"
+		}
+
+		details := []string{}
+		if param.Description != "" {
+			details = append(details, param.Description)
+		}
+		if param.Default != nil {
+			details = append(details, fmt.Sprintf("(default: %v)", param.Default))
+		}
+		if param.Required {
+			details = append(details, "(required)")
+		}
+
+		fmt.Fprintf(writer, "  %s\t%s\n", usage, strings.Join(details, " "))
+	}
+
+	return writer.Flush()
+}
@@ -0,0 +1,63 @@
+package project
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/LucasAVasco/falcula/arch"
+)
+
+// matchPlatform returns true if the platform ('os/arch') matches the pattern. A pattern without '/' only matches the OS (e.g. 'linux'),
+// the other patterns use the `path.Match` syntax (e.g. 'linux/arm64' or '*/arm64')
+func matchPlatform(pattern string, platform string) (bool, error) {
+	if !strings.Contains(pattern, "/") {
+		goos, _, _ := strings.Cut(platform, "/")
+		return pattern == goos, nil
+	}
+
+	match, err := path.Match(pattern, platform)
+	if err != nil {
+		return false, fmt.Errorf("invalid platform pattern '%s': %w", pattern, err)
+	}
+
+	return match, nil
+}
+
+// validatePlatforms returns an error if a platform pattern of the script is not valid
+func (s *Script) validatePlatforms() error {
+	for _, pattern := range s.Platforms {
+		_, err := matchPlatform(pattern, "")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CheckPlatform returns an error if the script (or task) can not run on the current platform (see `arch.GetCurrentPlatform`): the
+// platform does not match its `platforms` or there is no command for it
+func (s *Script) CheckPlatform() error {
+	platform := arch.GetCurrentPlatform()
+
+	if len(s.Platforms) > 0 {
+		supported := false
+		for _, pattern := range s.Platforms {
+			supported, _ = matchPlatform(pattern, platform) // Validated by `Validate`
+			if supported {
+				break
+			}
+		}
+
+		if !supported {
+			return fmt.Errorf("not supported on the current platform '%s' (only on: %s)", platform, strings.Join(s.Platforms, ", "))
+		}
+	}
+
+	if s.Command.Platforms != nil {
+		return fmt.Errorf("no command for the current platform '%s' (only for: %s)", platform, strings.Join(s.Command.getPlatformKeys(), ", "))
+	}
+
+	return nil
+}
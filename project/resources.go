@@ -0,0 +1,28 @@
+package project
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultSampleInterval is the default interval between the resource usage samples of the services
+const DefaultSampleInterval = 2 * time.Second
+
+// ResourcesConfig is the configuration of the resource usage sampling of the services
+type ResourcesConfig struct {
+	SampleInterval string `yaml:"sample_interval"` // Interval between the samples (e.g. '500ms'). '0' disables the sampling. Default: 2s
+}
+
+// GetSampleInterval returns the interval between the resource usage samples. Returns zero if the sampling is disabled
+func (r *ResourcesConfig) GetSampleInterval() (time.Duration, error) {
+	if r.SampleInterval == "" {
+		return DefaultSampleInterval, nil
+	}
+
+	interval, err := time.ParseDuration(r.SampleInterval)
+	if err != nil || interval < 0 {
+		return 0, fmt.Errorf("invalid sample interval '%s'", r.SampleInterval)
+	}
+
+	return interval, nil
+}
@@ -0,0 +1,117 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// schemaProvider is implemented by the configuration types that are not decoded from a YAML value of the same shape (custom
+// `UnmarshalYAML` methods). Returns their JSON Schema
+type schemaProvider interface {
+	jsonSchema() map[string]any
+}
+
+// jsonSchema is the JSON Schema of a command (see `Command.UnmarshalYAML`)
+func (s Command) jsonSchema() map[string]any {
+	single := []any{
+		map[string]any{"type": "string"},
+		map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "minItems": 1},
+	}
+
+	perPlatform := map[string]any{"type": "object", "additionalProperties": map[string]any{"oneOf": single}}
+	return map[string]any{"oneOf": append(single, perPlatform)}
+}
+
+// jsonSchema is the JSON Schema of a variable (see `Var.UnmarshalYAML`)
+func (v Var) jsonSchema() map[string]any {
+	type rawVar Var
+	return map[string]any{
+		"oneOf": []any{
+			map[string]any{"type": []string{"string", "number", "boolean"}},
+			getTypeSchema(reflect.TypeFor[rawVar]()),
+		},
+	}
+}
+
+// GetJSONSchema returns the JSON Schema of the project configuration file (`falcula.yaml`). It is generated from the `Config` type
+func GetJSONSchema() ([]byte, error) {
+	schema := getTypeSchema(reflect.TypeFor[Config]())
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "Falcula project configuration"
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error encoding JSON Schema: %w", err)
+	}
+
+	return append(data, '\n'), nil
+}
+
+// getTypeSchema returns the JSON Schema of a type. The structs only accept their fields (as the strict decoding of the configuration)
+func getTypeSchema(t reflect.Type) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		return getTypeSchema(t.Elem())
+	}
+
+	if provider, ok := reflect.Zero(t).Interface().(schemaProvider); ok {
+		return provider.jsonSchema()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": getTypeSchema(t.Elem())}
+
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": getTypeSchema(t.Elem())}
+
+	case reflect.Struct:
+		properties := map[string]any{}
+		addStructProperties(t, properties)
+		return map[string]any{"type": "object", "properties": properties, "additionalProperties": false}
+
+	default:
+		return map[string]any{} // Any value
+	}
+}
+
+// addStructProperties adds the fields of a struct (and of its inline structs) to the properties of a JSON Schema
+func addStructProperties(t reflect.Type, properties map[string]any) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if options == "inline" {
+			addStructProperties(field.Type, properties)
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		properties[name] = getTypeSchema(field.Type)
+	}
+}
@@ -2,7 +2,9 @@ package project
 
 import (
 	"fmt"
+	"maps"
 	"path/filepath"
+	"slices"
 )
 
 // Script is a falcula script. It can be either a shell command, a shell file, Lua code or a Lua file (can not be more than one of them)
@@ -13,6 +15,14 @@ type Script struct {
 	Lua     string  `yaml:"lua"`
 	File    string  `yaml:"file"`
 	LuaFile string  `yaml:"lua_file"`
+	Params  Params  `yaml:"params"`
+
+	Env map[string]string `yaml:"env"` // Environment variables of the commands
+
+	// Platforms where the script can run ('os' or 'os/arch', e.g. 'linux' or 'linux/arm64'). Supports all platforms if empty
+	Platforms []string `yaml:"platforms"`
+
+	templateErr error // Error of rendering the templates (see `render`)
 }
 
 // ConvertToAbsPath converts the paths of the script to absolute paths
@@ -72,5 +82,32 @@ func (s *Script) Validate() error {
 		return fmt.Errorf("multiple actions defined, only one is allowed")
 	}
 
+	err := s.validatePlatforms()
+	if err != nil {
+		return fmt.Errorf("error validating platforms: %w", err)
+	}
+
+	err = s.Params.Validate()
+	if err != nil {
+		return fmt.Errorf("error validating parameters: %w", err)
+	}
+
 	return nil
 }
+
+// GetEnv returns the environment variables of the script commands (`NAME=value`), sorted by name. The variables of the script override
+// the variables of its project
+func (s *Script) GetEnv() []string {
+	variables := map[string]string{}
+	if s.Project != nil {
+		maps.Copy(variables, s.Project.Env)
+	}
+	maps.Copy(variables, s.Env)
+
+	env := []string{}
+	for _, name := range slices.Sorted(maps.Keys(variables)) {
+		env = append(env, name+"="+variables[name])
+	}
+
+	return env
+}
@@ -3,4 +3,10 @@ package project
 // Task is a falcula task. It is built over the falcula Script type
 type Task struct {
 	Script `yaml:",inline"`
+	Deps   []string `yaml:"deps"` // Tasks to run before this task. Child project tasks use the `project:task` syntax
+
+	// The task is skipped if the source and generated files did not change since its last successful run. Globs relative to the task
+	// working directory (see `fingerprint.Glob`)
+	Sources   []string `yaml:"sources"`
+	Generates []string `yaml:"generates"`
 }
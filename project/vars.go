@@ -0,0 +1,262 @@
+package project
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/LucasAVasco/falcula/arch"
+	"github.com/LucasAVasco/falcula/sanitizer"
+	"github.com/Masterminds/sprig/v3"
+)
+
+// Var is a variable of the project configuration. Its value can be a static value, the value of an environment variable or the output of
+// a shell command (can not be more than one of them). The variables are available to the templates of the scripts and tasks as
+// `{{ .Vars.name }}`
+type Var struct {
+	Value   string `yaml:"value"`
+	Env     string `yaml:"env"`     // Name of the environment variable
+	Default string `yaml:"default"` // Value if the environment variable is not set
+	Shell   string `yaml:"shell"`   // Shell command. The trailing new lines of its output are removed
+
+	resolved string // Resolved value (see `Config.resolveVars`)
+}
+
+func (v *Var) UnmarshalYAML(unmarshal func(any) error) error {
+	// Try parsing as a static value first
+	var value string
+	if err := unmarshal(&value); err == nil {
+		v.Value = value
+		return nil
+	}
+
+	// Fallback to parsing as a mapping (keeps the position of the errors)
+	type rawVar Var
+	return unmarshal((*rawVar)(v))
+}
+
+// resolve returns the value of the variable. The static value and the shell command are templates (see `renderTemplate`). The shell
+// command runs in the provided directory
+func (v *Var) resolve(dir string, data *templateData) (string, error) {
+	numSources := 0
+	for _, source := range []string{v.Value, v.Env, v.Shell} {
+		if source != "" {
+			numSources++
+		}
+	}
+	if numSources > 1 {
+		return "", fmt.Errorf("only one of 'value', 'env' and 'shell' is allowed")
+	}
+
+	switch {
+	case v.Env != "":
+		value, ok := os.LookupEnv(v.Env)
+		if !ok {
+			return v.Default, nil
+		}
+		return value, nil
+
+	case v.Shell != "":
+		command, err := renderTemplate(v.Shell, data)
+		if err != nil {
+			return "", fmt.Errorf("error rendering shell command: %w", err)
+		}
+
+		return runShellVar(dir, command)
+
+	default:
+		return renderTemplate(v.Value, data)
+	}
+}
+
+// MarshalYAML shows the variable as its resolved value. The variables from an environment variable or a shell command also show their
+// definition, so the rendered configuration shows where the value came from
+func (v *Var) MarshalYAML() (any, error) {
+	if v.Env == "" && v.Shell == "" {
+		return v.resolved, nil
+	}
+
+	type rawVar Var
+	return &rawVar{Value: v.resolved, Env: v.Env, Default: v.Default, Shell: v.Shell}, nil
+}
+
+// shellVarResult is the memoized output of the shell command of a variable
+type shellVarResult struct {
+	once   sync.Once
+	output string
+	err    error
+}
+
+var (
+	shellVarResultsMutex sync.Mutex
+	shellVarResults      = map[[2]string]*shellVarResult{} // Indexed by the directory and the command
+)
+
+// runShellVar runs the shell command of a variable in the provided directory and returns its output without the trailing new lines. The
+// configuration is read multiple times by the same process (e.g. the child projects and the dependencies of the tasks), so each command
+// runs once per process and directory
+func runShellVar(dir string, command string) (string, error) {
+	shellVarResultsMutex.Lock()
+	result, ok := shellVarResults[[2]string{dir, command}]
+	if !ok {
+		result = &shellVarResult{}
+		shellVarResults[[2]string{dir, command}] = result
+	}
+	shellVarResultsMutex.Unlock()
+
+	result.once.Do(func() {
+		cmd := exec.Command("sh", "-c", command)
+		cmd.Dir = dir
+		cmd.Stderr = os.Stderr
+		output, err := cmd.Output()
+		if err != nil {
+			result.err = fmt.Errorf("error running shell command '%s': %w", command, err)
+			return
+		}
+		result.output = strings.TrimRight(string(output), "\r\n")
+	})
+
+	return result.output, result.err
+}
+
+// templateData is the data available to the templates of the project configuration
+type templateData struct {
+	Vars   map[string]string
+	Folder string // Project folder
+}
+
+// renderTemplate renders a Go template (with the sprig functions) of the project configuration. Strings without templates are returned
+// as they are. Strings that are not valid templates and do not use the template data (`.Vars` and `.Folder`) are also returned as they are,
+// because they can be templates of the commands (e.g. `docker ps --format '{{.Names}}'`)
+func renderTemplate(str string, data *templateData) (string, error) {
+	if !strings.Contains(str, "{{") {
+		return str, nil
+	}
+
+	rendered, err := executeTemplate(str, data)
+	if err != nil && !strings.Contains(str, ".Vars") && !strings.Contains(str, ".Folder") {
+		return str, nil
+	}
+
+	return rendered, err
+}
+
+// executeTemplate executes a Go template (with the sprig functions) of the project configuration
+func executeTemplate(str string, data *templateData) (string, error) {
+	str, err := sanitizer.SanitizeTemplate(str, "")
+	if err != nil {
+		return "", fmt.Errorf("error sanitizing template: %w", err)
+	}
+
+	tpl, err := template.New("main").Funcs(sprig.FuncMap()).Option("missingkey=error").Parse(str)
+	if err != nil {
+		return "", fmt.Errorf("error parsing template: %w", err)
+	}
+
+	writer := &bytes.Buffer{}
+	err = tpl.Execute(writer, data)
+	if err != nil {
+		return "", fmt.Errorf("error executing template: %w", err)
+	}
+
+	return writer.String(), nil
+}
+
+// resolveVars resolves the variables of the project. The templates of the variables can use the other variables of the project and the
+// variables of the parent project. A project variable overrides the parent variable with the same name, but its own template can use the
+// parent value (e.g. `tag: 'dev-{{ .Vars.tag }}'`). The definitions keep their resolved values
+func (c *Config) resolveVars(parentVars map[string]string) error {
+	vars := map[string]string{}
+	for name, value := range parentVars {
+		if _, ok := c.Vars[name]; !ok {
+			vars[name] = value
+		}
+	}
+
+	// The variables are resolved in multiple passes because they can depend on each other. A pass that does not resolve any variable means
+	// that the remaining variables use undefined variables (or depend on each other)
+	pending := slices.Sorted(maps.Keys(c.Vars))
+	for len(pending) > 0 {
+		var errs []error
+		remaining := []string{}
+
+		for _, name := range pending {
+			data := &templateData{Vars: maps.Clone(vars), Folder: c.Folder}
+			if parentValue, ok := parentVars[name]; ok {
+				data.Vars[name] = parentValue
+			}
+
+			value, err := c.Vars[name].resolve(c.Folder, data)
+			if err != nil {
+				err = fmt.Errorf("error resolving variable '%s': %w", name, err)
+				if !errors.As(err, new(template.ExecError)) {
+					return err // Only the template execution errors can be caused by a variable that is not resolved yet
+				}
+
+				errs = append(errs, err)
+				remaining = append(remaining, name)
+				continue
+			}
+
+			vars[name] = value
+		}
+
+		if len(remaining) == len(pending) {
+			return errors.Join(errs...)
+		}
+		pending = remaining
+	}
+
+	for name, v := range c.Vars {
+		v.resolved = vars[name]
+	}
+
+	c.resolvedVars = vars
+	return nil
+}
+
+// render resolves the command of the current platform and renders the templates of the script (command, working directory, files and
+// environment variables). The errors are kept by the script (see `CheckTemplates`), so they only fail the script when it runs
+func (s *Script) render(data *templateData) {
+	s.templateErr = s.renderFields(data)
+}
+
+// CheckTemplates returns the error of rendering the templates of the script (see `render`)
+func (s *Script) CheckTemplates() error {
+	return s.templateErr
+}
+
+// renderFields renders the templates of the script fields. Returns the first error
+func (s *Script) renderFields(data *templateData) error {
+	s.Command.resolvePlatform(arch.GetCurrentPlatform())
+
+	fields := []*string{&s.Cwd, &s.File, &s.LuaFile, &s.Command.String}
+	for i := range s.Command.List {
+		fields = append(fields, &s.Command.List[i])
+	}
+
+	for _, field := range fields {
+		value, err := renderTemplate(*field, data)
+		if err != nil {
+			return err
+		}
+		*field = value
+	}
+
+	for name, value := range s.Env {
+		value, err := renderTemplate(value, data)
+		if err != nil {
+			return fmt.Errorf("error rendering environment variable '%s': %w", name, err)
+		}
+		s.Env[name] = value
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package falcula
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/LucasAVasco/falcula/colorgen"
+	"github.com/LucasAVasco/falcula/multiplexer"
+	"github.com/LucasAVasco/falcula/project"
+	"github.com/fatih/color"
+)
+
+// AllProjectsOptions are the options to run a script or task in all child projects. All fields are optional
+type AllProjectsOptions struct {
+	Projects    string // Only runs in the projects whose name matches this glob (`path.Match` syntax, e.g. 'packages:*')
+	Concurrency int    // Maximum number of projects running at the same time. Uses the number of CPUs if not positive
+}
+
+// projectRun is the run of a script or task in a child project
+type projectRun struct {
+	name     string
+	project  *project.Config
+	err      error
+	duration time.Duration
+}
+
+// RunTaskInAllProjects runs a task in every child project (recursively) that defines it and supports the current platform
+func (a *App) RunTaskInAllProjects(taskId string, args []string, opts *AllProjectsOptions) error {
+	taskName, _, _ := strings.Cut(taskId, ".")
+
+	return a.runInAllProjects("task", taskId, args, opts, func(config *project.Config) bool {
+		task, ok := config.Tasks[taskName]
+		return ok && task.CheckPlatform() == nil
+	})
+}
+
+// RunScriptInAllProjects runs a script in every child project (recursively) that defines it and supports the current platform
+func (a *App) RunScriptInAllProjects(scriptName string, args []string, opts *AllProjectsOptions) error {
+	return a.runInAllProjects("script", scriptName, args, opts, func(config *project.Config) bool {
+		script, ok := config.Scripts[scriptName]
+		return ok && script.CheckPlatform() == nil
+	})
+}
+
+// runInAllProjects runs a script or task (kind) in the child projects where it is defined. Each one runs in a new Falcula process (in raw
+// mode), with its output prefixed by the project name. Prints a summary at the end and returns an error if any run failed
+func (a *App) runInAllProjects(kind, name string, args []string, opts *AllProjectsOptions, isDefined func(*project.Config) bool) error {
+	if opts == nil {
+		opts = &AllProjectsOptions{}
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+
+	// Projects
+	projects, err := a.project.GetAllProjects()
+	if err != nil {
+		return fmt.Errorf("error getting child projects: %w", err)
+	}
+
+	runs := []*projectRun{}
+	for _, projectName := range slices.Sorted(maps.Keys(projects)) {
+		if opts.Projects != "" {
+			match, err := path.Match(opts.Projects, projectName)
+			if err != nil {
+				return fmt.Errorf("invalid projects glob '%s': %w", opts.Projects, err)
+			}
+			if !match {
+				continue
+			}
+		}
+
+		if isDefined(projects[projectName]) {
+			runs = append(runs, &projectRun{name: projectName, project: projects[projectName]})
+		}
+	}
+
+	if len(runs) == 0 {
+		return fmt.Errorf("no child project defines the %s '%s' for the current platform", kind, name)
+	}
+
+	// Output of the projects
+	prefixWidth := 0
+	for _, run := range runs {
+		prefixWidth = max(prefixWidth, len(run.name))
+	}
+
+	multi := multiplexer.New(func(entry *multiplexer.Entry) error {
+		output := os.Stdout
+		if entry.Client.GetLevel() == "stderr" {
+			output = os.Stderr
+		}
+
+		prefix := entry.Client.GetColor().Sprintf("%-*s |", prefixWidth, entry.Client.GetName())
+		_, err := fmt.Fprintf(output, "%s %s\n", prefix, entry.Line)
+		return err
+	}, nil)
+
+	// Runs
+	semaphore := make(chan struct{}, concurrency)
+	waitGroup := sync.WaitGroup{}
+	for _, run := range runs {
+		clr := colorgen.Next()
+		waitGroup.Go(func() {
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			start := time.Now()
+			run.err = a.runInProject(kind, name, args, run, multi, clr)
+			run.duration = time.Since(start)
+		})
+	}
+	waitGroup.Wait()
+
+	err = multi.Close()
+	if err != nil {
+		return fmt.Errorf("error closing output multiplexer: %w", err)
+	}
+
+	return printProjectRunsSummary(runs)
+}
+
+// runInProject runs a script or task (kind) in a child project. The output is sent to the multiplexer
+func (a *App) runInProject(kind, name string, args []string, run *projectRun, multi *multiplexer.Multiplexer, clr *color.Color) error {
+	cmdArgs := []string{kind, "run", "--raw"}
+	if a.keepLog {
+		cmdArgs = append(cmdArgs, "--keep-log")
+	}
+	if a.dryRunPlan != nil {
+		cmdArgs = append(cmdArgs, "--dry-run")
+	}
+	if kind == "task" && a.noDeps {
+		cmdArgs = append(cmdArgs, "--no-deps")
+	}
+	if kind == "task" && a.force {
+		cmdArgs = append(cmdArgs, "--force")
+	}
+	cmdArgs = append(cmdArgs, name)
+	cmdArgs = append(cmdArgs, args...)
+
+	cmd, err := newFalculaCmd(run.project.Folder, cmdArgs...)
+	if err != nil {
+		return err
+	}
+
+	stdout := multi.NewClient(run.name, "stdout", clr)
+	stderr := multi.NewClient(run.name, "stderr", clr)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err = cmd.Run()
+	return errors.Join(err, stdout.Flush(), stderr.Flush())
+}
+
+// printProjectRunsSummary prints if each run passed or failed. Returns an error if any run failed
+func printProjectRunsSummary(runs []*projectRun) error {
+	fmt.Println()
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	failed := 0
+	for _, run := range runs {
+		duration := run.duration.Round(time.Millisecond)
+		if run.err != nil {
+			failed++
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", color.RedString("FAIL"), run.name, duration, run.err)
+		} else {
+			fmt.Fprintf(writer, "%s\t%s\t%s\n", color.GreenString("PASS"), run.name, duration)
+		}
+	}
+
+	err := writer.Flush()
+	if err != nil {
+		return fmt.Errorf("error writing summary: %w", err)
+	}
+
+	fmt.Printf("passed: %d, failed: %d\n", len(runs)-failed, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d projects failed", failed, len(runs))
+	}
+
+	return nil
+}
@@ -87,3 +87,7 @@ func (p *ParallelProcessesStep) Abort(force bool) (*iface.ExitInfo, error) {
 
 	return &iface.ExitInfo{}, nil
 }
+
+func (p *ParallelProcessesStep) GetPids() []int {
+	return getRunningPids(p.processes...)
+}
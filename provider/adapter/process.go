@@ -36,3 +36,19 @@ func (p *ProcessStep) Wait() (*iface.ExitInfo, error) {
 func (p *ProcessStep) Abort(force bool) (*iface.ExitInfo, error) {
 	return p.process.Stop(force)
 }
+
+func (p *ProcessStep) GetPids() []int {
+	return getRunningPids(p.process)
+}
+
+// getRunningPids returns the PIDs of the processes that are running
+func getRunningPids(processes ...*process.Process) []int {
+	pids := []int{}
+	for _, proc := range processes {
+		if pid := proc.GetPid(); pid != 0 {
+			pids = append(pids, pid)
+		}
+	}
+
+	return pids
+}
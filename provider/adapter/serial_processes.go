@@ -76,3 +76,7 @@ func (s *SerialProcessesStep) Abort(force bool) (*iface.ExitInfo, error) {
 
 	return &iface.ExitInfo{}, nil
 }
+
+func (s *SerialProcessesStep) GetPids() []int {
+	return getRunningPids(s.processes...)
+}
@@ -4,6 +4,7 @@ package base
 import (
 	"github.com/LucasAVasco/falcula/colorgen"
 	"github.com/LucasAVasco/falcula/multiplexer"
+	"github.com/LucasAVasco/falcula/process"
 	"github.com/LucasAVasco/falcula/service/iface"
 
 	"github.com/fatih/color"
@@ -18,7 +19,8 @@ type ProviderOpts struct {
 type ProviderConfig struct {
 	Multiplexer *multiplexer.Multiplexer
 	Name        string
-	Opts        ProviderOpts // The options for the provider
+	Opts        ProviderOpts     // The options for the provider
+	Recorder    process.Recorder // Records the processes of the services instead of spawning them (dry run). Optional
 }
 
 // Provider represents a provider. Provides the basic data required by a provider. Any provider should inherit from this
@@ -51,6 +53,7 @@ func (p *Provider) NewService(name string, opts *ServiceOpts) *Service {
 		Color:       p.Color,
 		Name:        name,
 		Opts:        p.Config.Opts.DefaultServiceOpts,
+		Recorder:    p.Config.Recorder,
 	}
 
 	return NewService(&config, opts)
@@ -13,11 +13,15 @@ type ServiceConfig struct {
 	Color       *color.Color
 	Name        string
 	Opts        iface.Opts
+	Recorder    process.Recorder // Records the processes instead of spawning them (dry run). Optional
 }
 
 // ServiceOpts is a structure that holds the options for a service. It is optional
 type ServiceOpts struct {
-	StartDisabled *bool `lua:"start_disabled"`
+	StartDisabled *bool                      `lua:"start_disabled"`
+	LogFormat     *string                    `lua:"log_format"`
+	OnOutput      []*iface.OutputTrigger     `lua:"on_output"`
+	Thresholds    []*iface.ResourceThreshold `lua:"thresholds"`
 }
 
 // Service represents a base service with the basic data required by a service. Any service should inherit from this
@@ -35,6 +39,18 @@ func NewService(config *ServiceConfig, opts *ServiceOpts) *Service {
 		if opts.StartDisabled != nil {
 			s.Config.Opts.StartDisabled = *opts.StartDisabled
 		}
+
+		if opts.LogFormat != nil {
+			s.Config.Opts.LogFormat = *opts.LogFormat
+		}
+
+		if opts.OnOutput != nil {
+			s.Config.Opts.OnOutput = opts.OnOutput
+		}
+
+		if opts.Thresholds != nil {
+			s.Config.Opts.Thresholds = opts.Thresholds
+		}
 	}
 
 	return &s
@@ -48,7 +64,8 @@ func (s *Service) GetOpts() *iface.Opts {
 	return &s.Config.Opts
 }
 
-// NewProcessOptions returns a new process.Options struct configured for the service. It will use the provider multiplexer and color.
+// NewProcessOptions returns a new process.Options struct configured for the service. It will use the provider multiplexer, color and
+// recorder.
 //
 // It will also set `Wait` to `false` and `ManualStart` to `true`. If you use the process adapters at '../adapter/', you do not need to
 // manually start the process because they automatically starts the process if it is not already started
@@ -59,5 +76,6 @@ func (s *Service) NewProcessOptions() *process.Options {
 		Wait:        false,
 		ManualStart: true,
 		Color:       s.Config.Color,
+		Recorder:    s.Config.Recorder,
 	}
 }
@@ -2,6 +2,7 @@
 package process
 
 import (
+	"github.com/LucasAVasco/falcula/process"
 	"github.com/LucasAVasco/falcula/provider/base"
 )
 
@@ -10,8 +11,12 @@ type Command struct {
 	Dir     string   // Working directory. If empty, the current working directory will be used
 	Shell   bool     // true to run the command in a shell
 	Command []string // Command and its arguments
+	Limits  *Limits  // Resource limits of the process. Nil if not limited
 }
 
+// Limits are the resource limits of a command
+type Limits = process.Limits
+
 // ProviderOpts is the options for a process provider
 type ProviderOpts = base.ProviderOpts
 
@@ -28,6 +28,7 @@ func (s *Service) Prepare(callback iface.OnExitCallback) (iface.Step, error) {
 	procOpts := s.NewProcessOptions()
 	procOpts.Dir = s.prepareCmd.Dir
 	procOpts.Shell = s.prepareCmd.Shell
+	procOpts.Limits = s.prepareCmd.Limits
 	procOpts.OnExit = func(info *process.ExitInfo) { callback(info, nil) }
 
 	proc, err := process.New(procOpts, s.prepareCmd.Command[0], s.prepareCmd.Command[1:]...)
@@ -47,6 +48,7 @@ func (s *Service) Start(callback iface.OnExitCallback) (iface.Step, error) {
 	procOpts := s.NewProcessOptions()
 	procOpts.Dir = s.mainCmd.Dir
 	procOpts.Shell = s.mainCmd.Shell
+	procOpts.Limits = s.mainCmd.Limits
 	procOpts.OnExit = func(info *process.ExitInfo) { callback(info, nil) }
 
 	// Starts the process
@@ -0,0 +1,168 @@
+// Package redact masks secrets (registered values, values of environment variables and regular expressions) in log messages
+package redact
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Mask replaces the secrets in the redacted text
+const Mask = "[REDACTED]"
+
+// MinValueLength is the minimum length of a secret value. Shorter values are ignored because masking them would hide unrelated text (e.g.
+// an environment variable with the value '1')
+const MinValueLength = 4
+
+// Config is the redaction configuration. Used to configure the redactor from the project configuration file
+type Config struct {
+	Env      []string `yaml:"env"`      // Globs matched against the environment variable names (e.g. '*_TOKEN'). Their values are secrets
+	Patterns []string `yaml:"patterns"` // Regular expressions. If a pattern has groups, only the groups are masked, otherwise the whole match
+}
+
+// Redactor masks secrets in texts. It is thread-safe
+type Redactor struct {
+	mutex    sync.RWMutex
+	values   []string
+	replacer *strings.Replacer // Replaces the values. Nil if there are no values
+	patterns []*regexp.Regexp
+}
+
+// New creates an empty redactor
+func New() *Redactor {
+	return &Redactor{
+		values:   make([]string, 0),
+		patterns: make([]*regexp.Regexp, 0),
+	}
+}
+
+// AddConfig adds the secrets of a configuration. The environment variables are read from `environ` (format of `os.Environ`)
+func (r *Redactor) AddConfig(config *Config, environ []string) error {
+	err := r.AddEnv(config.Env, environ)
+	if err != nil {
+		return err
+	}
+
+	for _, pattern := range config.Patterns {
+		err := r.AddPattern(pattern)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddValue adds a secret value. Values shorter than `MinValueLength` are ignored
+func (r *Redactor) AddValue(value string) {
+	if len(value) < MinValueLength {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if slices.Contains(r.values, value) {
+		return
+	}
+
+	// The longest values are replaced first, so a value that contains another one is completely masked
+	r.values = append(r.values, value)
+	slices.SortFunc(r.values, func(a, b string) int {
+		return len(b) - len(a)
+	})
+
+	oldNew := make([]string, 0, 2*len(r.values))
+	for _, value := range r.values {
+		oldNew = append(oldNew, value, Mask)
+	}
+	r.replacer = strings.NewReplacer(oldNew...)
+}
+
+// AddEnv adds the values of the environment variables whose names match any of the globs (`path.Match` syntax). The environment variables
+// are read from `environ` (format of `os.Environ`)
+func (r *Redactor) AddEnv(globs []string, environ []string) error {
+	for _, glob := range globs {
+		_, err := path.Match(glob, "")
+		if err != nil {
+			return fmt.Errorf("invalid environment variable glob '%s': %w", glob, err)
+		}
+	}
+
+	for _, variable := range environ {
+		name, value, _ := strings.Cut(variable, "=")
+		for _, glob := range globs {
+			matched, _ := path.Match(glob, name)
+			if matched {
+				r.AddValue(value)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// AddPattern adds a regular expression (`regexp` syntax). If it has groups, only the groups are masked (e.g. 'password=(\S+)'), otherwise
+// the whole match is masked
+func (r *Redactor) AddPattern(pattern string) error {
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid secret pattern '%s': %w", pattern, err)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.patterns = append(r.patterns, regex)
+	return nil
+}
+
+// Redact masks the secrets of a text
+func (r *Redactor) Redact(text string) string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if r.replacer != nil {
+		text = r.replacer.Replace(text)
+	}
+
+	for _, regex := range r.patterns {
+		text = redactPattern(regex, text)
+	}
+
+	return text
+}
+
+// redactPattern masks the matches of a regular expression. Only the groups are masked if the expression has groups
+func redactPattern(regex *regexp.Regexp, text string) string {
+	if regex.NumSubexp() == 0 {
+		return regex.ReplaceAllLiteralString(text, Mask)
+	}
+
+	matches := regex.FindAllStringSubmatchIndex(text, -1)
+	if matches == nil {
+		return text
+	}
+
+	builder := strings.Builder{}
+	last := 0
+	for _, match := range matches {
+		for group := 1; group <= regex.NumSubexp(); group++ {
+			start, end := match[2*group], match[2*group+1]
+			if start < last || start < 0 { // Group not matched or nested in a previous group
+				continue
+			}
+
+			builder.WriteString(text[last:start])
+			builder.WriteString(Mask)
+			last = end
+		}
+	}
+	builder.WriteString(text[last:])
+
+	return builder.String()
+}
@@ -0,0 +1,116 @@
+package falcula
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/LucasAVasco/falcula/lua/luaruntime"
+	"github.com/LucasAVasco/falcula/lua/modules/modmanager"
+)
+
+// Errors returned in raw mode when the run fails
+var (
+	ErrServicesFailed = errors.New("at least one service failed")
+	ErrScriptFailed   = errors.New("the script failed (the error is logged above)")
+)
+
+// reportEnabled checks if the report of the run (final state of the services) is generated. Raw mode always shows it. A dry run has no
+// report because the services do not run
+func (a *App) reportEnabled() bool {
+	if a.dryRunPlan != nil {
+		return false
+	}
+
+	return a.rawMode || a.reportJSON != "" || a.reportJUnit != ""
+}
+
+// getManagerCallbacks returns the callbacks of the manager module that are not related to the TUI (web dashboard, report and dry run).
+// Returns nil if there is none
+func (a *App) getManagerCallbacks() *modmanager.Callbacks {
+	callbacks := []*modmanager.Callbacks{}
+
+	if a.webServer != nil {
+		callbacks = append(callbacks, a.webServer.GetManagerCallbacks())
+	}
+
+	if a.reportCollector != nil {
+		callbacks = append(callbacks, a.reportCollector.GetManagerCallbacks())
+	}
+
+	if a.dryRunPlan != nil {
+		callbacks = append(callbacks, a.dryRunPlan.GetManagerCallbacks())
+	}
+
+	if len(callbacks) == 0 {
+		return nil
+	}
+
+	return modmanager.JoinCallbacks(callbacks...)
+}
+
+// resetReport forgets the services of the previous run (e.g. the script is re-run)
+func (a *App) resetReport() {
+	if a.reportCollector != nil {
+		a.reportCollector.Reset()
+	}
+}
+
+// finishReport generates the report of the run (if enabled) and returns an error if the script failed, so a failed task is not considered
+// up to date. The name is the script or task that ran and the script error is optional
+func (a *App) finishReport(runtime *luaruntime.Runtime, name string, scriptErr error) error {
+	if a.reportCollector != nil {
+		err := a.writeReport(runtime, name, scriptErr)
+		if err != nil {
+			return err
+		}
+	}
+
+	if scriptErr != nil {
+		return ErrScriptFailed
+	}
+
+	return nil
+}
+
+// writeReport writes the report of the run to the report files. In raw mode, logs the report as a table and returns an error if any
+// service failed
+func (a *App) writeReport(runtime *luaruntime.Runtime, name string, scriptErr error) error {
+	runReport := a.reportCollector.GetReport(name, scriptErr)
+	errs := []error{}
+
+	if a.reportJSON != "" {
+		errs = append(errs, runReport.WriteJSONFile(a.reportJSON))
+	}
+
+	if a.reportJUnit != "" {
+		errs = append(errs, runReport.WriteJUnitFile(a.reportJUnit))
+	}
+
+	err := errors.Join(errs...)
+	if err != nil {
+		runtime.Logger.LogError(fmt.Errorf("error writing report: %w", err))
+	}
+
+	if !a.rawMode {
+		return nil
+	}
+
+	// Summary
+	if len(runReport.Services) > 0 {
+		table := strings.Builder{}
+		runReport.WriteTable(&table)
+		runtime.Logger.LogDebug("\n" + table.String())
+	}
+
+	if failed := runReport.GetFailedServices(); len(failed) > 0 {
+		names := make([]string, 0, len(failed))
+		for _, svc := range failed {
+			names = append(names, svc.Service)
+		}
+
+		return fmt.Errorf("%w: %s", ErrServicesFailed, strings.Join(names, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/LucasAVasco/falcula/service/status"
+)
+
+// JUnit XML format. Each service is a test case of a test suite named after the run
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure"`
+	Error     *junitMessage `xml:"error"`
+	Skipped   *junitMessage `xml:"skipped"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnitFile writes the report to a JUnit XML file. The services are the test cases (their manager is the class name), the disabled
+// services are skipped and a script error is reported as an error of the test suite
+func (r *Report) WriteJUnitFile(path string) error {
+	suite := junitTestSuite{
+		Name:      r.Name,
+		Time:      formatJUnitSeconds(r.Duration),
+		Timestamp: r.StartTime.Format("2006-01-02T15:04:05"),
+		Cases:     make([]junitTestCase, 0, len(r.Services)),
+	}
+
+	for _, svc := range r.Services {
+		testCase := junitTestCase{
+			ClassName: svc.Manager,
+			Name:      svc.Service,
+			Time:      formatJUnitSeconds(svc.Duration),
+			SystemOut: fmt.Sprintf("status: %s, restarts: %d", svc.Status, svc.Restarts),
+		}
+
+		switch {
+		case svc.Failed():
+			message := "service ended with error"
+			if svc.ExitCode != nil {
+				message = fmt.Sprintf("service ended with error (exit code %d)", *svc.ExitCode)
+			}
+
+			testCase.Failure = &junitMessage{Message: message, Text: svc.Error}
+			suite.Failures++
+
+		case svc.Status == status.Disabled.ToString():
+			testCase.Skipped = &junitMessage{Message: "service is disabled"}
+			suite.Skipped++
+		}
+
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	if r.ScriptError != "" {
+		suite.Cases = append(suite.Cases, junitTestCase{
+			ClassName: "script",
+			Name:      r.Name,
+			Time:      formatJUnitSeconds(r.Duration),
+			Error:     &junitMessage{Message: "script error", Text: r.ScriptError},
+		})
+		suite.Errors++
+	}
+
+	suite.Tests = len(suite.Cases)
+
+	suites := junitTestSuites{
+		Name:     r.Name,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	content, err := xml.MarshalIndent(&suites, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding JUnit report: %w", err)
+	}
+
+	content = append([]byte(xml.Header), content...)
+	err = os.WriteFile(path, append(content, '\n'), 0644)
+	if err != nil {
+		return fmt.Errorf("error writing JUnit report '%s': %w", path, err)
+	}
+
+	return nil
+}
+
+func formatJUnitSeconds(seconds float64) string {
+	return strconv.FormatFloat(seconds, 'f', 3, 64)
+}
@@ -0,0 +1,187 @@
+// Package report implements the report of a run: the final state of each service used by a script or task. Used by the CI to know if the
+// services failed
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/LucasAVasco/falcula/lua/modules/modmanager"
+	"github.com/LucasAVasco/falcula/service/enhanced"
+	"github.com/LucasAVasco/falcula/service/manager"
+	"github.com/LucasAVasco/falcula/service/status"
+)
+
+// Report is the final state of the services of a run
+type Report struct {
+	Name        string           `json:"name"` // Script or task that ran (e.g. 'script dev')
+	StartTime   time.Time        `json:"start_time"`
+	Duration    float64          `json:"duration_seconds"`
+	Success     bool             `json:"success"`                // No service ended with error and the script has no error
+	ScriptError string           `json:"script_error,omitempty"` // Error of the Lua script
+	Services    []*ServiceReport `json:"services"`
+}
+
+// ServiceReport is the final state of a service
+type ServiceReport struct {
+	Manager  string  `json:"manager"`
+	Service  string  `json:"service"`
+	Status   string  `json:"status"`
+	ExitCode *int    `json:"exit_code"`        // Exit code of the last step that ended. Nil if no step ended
+	Duration float64 `json:"duration_seconds"` // Duration of the last execution of the main step
+	Restarts uint    `json:"restarts"`
+	Error    string  `json:"error,omitempty"` // Error of the last step that ended
+}
+
+// Failed checks if the service ended with error
+func (s *ServiceReport) Failed() bool {
+	return s.Status == status.Error.ToString()
+}
+
+// Collector collects the services created by a run. Its callbacks must be provided to the manager module (see
+// `modules.AllModulesLoaderOptions`)
+type Collector struct {
+	mutex     sync.Mutex
+	startTime time.Time
+	services  []collectedService
+}
+
+type collectedService struct {
+	manager *manager.Manager
+	service *enhanced.EnhancedService
+}
+
+// NewCollector creates a collector
+func NewCollector() *Collector {
+	return &Collector{
+		startTime: time.Now(),
+	}
+}
+
+// Reset forgets the collected services and restarts the run time (e.g. the script is re-run)
+func (c *Collector) Reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.startTime = time.Now()
+	c.services = nil
+}
+
+// GetManagerCallbacks returns the callbacks that collect the services
+func (c *Collector) GetManagerCallbacks() *modmanager.Callbacks {
+	return &modmanager.Callbacks{
+		OnAddService: func(man *manager.Manager, svc *enhanced.EnhancedService) {
+			c.mutex.Lock()
+			defer c.mutex.Unlock()
+
+			c.services = append(c.services, collectedService{manager: man, service: svc})
+		},
+	}
+}
+
+// GetReport returns the report with the current state of the collected services. The script error is optional
+func (c *Collector) GetReport(name string, scriptErr error) *Report {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	report := Report{
+		Name:      name,
+		StartTime: c.startTime,
+		Duration:  time.Since(c.startTime).Seconds(),
+		Success:   scriptErr == nil,
+		Services:  make([]*ServiceReport, 0, len(c.services)),
+	}
+
+	if scriptErr != nil {
+		report.ScriptError = scriptErr.Error()
+	}
+
+	for _, collected := range c.services {
+		svc := collected.service
+		stats := svc.GetStats()
+
+		serviceReport := ServiceReport{
+			Manager:  collected.manager.GetName(),
+			Service:  svc.GetName(),
+			Status:   svc.GetStatus().ToString(),
+			Duration: svc.GetUptime().Seconds(),
+			Restarts: stats.Restarts,
+		}
+
+		if stats.LastExitInfo != nil {
+			code := int(stats.LastExitInfo.Code)
+			serviceReport.ExitCode = &code
+
+			if err := stats.LastExitInfo.WrapError(); err != nil {
+				serviceReport.Error = err.Error()
+			}
+		}
+		if stats.LastError != nil {
+			serviceReport.Error = stats.LastError.Error()
+		}
+
+		if serviceReport.Failed() {
+			report.Success = false
+		}
+
+		report.Services = append(report.Services, &serviceReport)
+	}
+
+	return &report
+}
+
+// GetFailedServices returns the services that ended with error
+func (r *Report) GetFailedServices() []*ServiceReport {
+	failed := []*ServiceReport{}
+	for _, svc := range r.Services {
+		if svc.Failed() {
+			failed = append(failed, svc)
+		}
+	}
+
+	return failed
+}
+
+// WriteTable writes the report as a table
+func (r *Report) WriteTable(w io.Writer) error {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "MANAGER\tSERVICE\tSTATUS\tEXIT CODE\tDURATION\tRESTARTS")
+
+	for _, svc := range r.Services {
+		exitCode := "-"
+		if svc.ExitCode != nil {
+			exitCode = strconv.Itoa(*svc.ExitCode)
+		}
+
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%d\n", svc.Manager, svc.Service, svc.Status, exitCode, formatSeconds(svc.Duration),
+			svc.Restarts)
+	}
+
+	return writer.Flush()
+}
+
+// WriteJSONFile writes the report to a JSON file
+func (r *Report) WriteJSONFile(path string) error {
+	content, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding report: %w", err)
+	}
+
+	err = os.WriteFile(path, append(content, '\n'), 0644)
+	if err != nil {
+		return fmt.Errorf("error writing JSON report '%s': %w", path, err)
+	}
+
+	return nil
+}
+
+// formatSeconds formats a duration in seconds rounded to milliseconds
+func formatSeconds(seconds float64) string {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Millisecond).String()
+}
@@ -2,39 +2,105 @@ package falcula
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
 
-	"github.com/LucasAVasco/falcula/lua/luaruntime"
 	"github.com/LucasAVasco/falcula/lua/modules/modtui"
 	"github.com/LucasAVasco/falcula/process"
 	"github.com/LucasAVasco/falcula/project"
 )
 
-// GetScriptList returns all the scripts in the current project and its children projects
-func (a *App) GetScriptList() (map[string]*project.Script, error) {
-	return a.project.GetAllScripts()
+// GetScriptList returns all the scripts in the current project and its children projects. Hides the scripts that can not run on the current
+// platform unless all is true
+func (a *App) GetScriptList(all bool) (map[string]*project.Script, error) {
+	scripts, err := a.project.GetAllScripts()
+	if err != nil {
+		return nil, err
+	}
+
+	if !all {
+		maps.DeleteFunc(scripts, func(name string, script *project.Script) bool {
+			return script.CheckPlatform() != nil
+		})
+	}
+
+	return scripts, nil
+}
+
+// GetScriptParams returns the parameters of a script
+func (a *App) GetScriptParams(scriptName string) (project.Params, error) {
+	script, err := a.project.GetScriptByName(scriptName)
+	if err != nil {
+		return nil, err
+	}
+
+	return script.Params, nil
 }
 
 // RunScript runs a script of the current project with the given arguments. The scriptName can be the name of a named script or the path to
 // a script
 func (a *App) RunScript(scriptName string, args ...string) error {
 	// Lua runtime
-	runtime, err := luaruntime.New()
+	runtime, err := a.newRuntime()
 	if err != nil {
 		return fmt.Errorf("error creating runtime: %w", err)
 	}
 	defer runtime.Close()
 
-	// modtui does not closes the TUI when it is closed. The TUI is persistent across runs. Need to close it manually
-	defer modtui.ClosePersistentTui()
-
 	// Script to run
 	script, err := a.project.GetScriptByName(scriptName)
 	if err != nil {
 		return fmt.Errorf("error getting script to run: %w", err)
 	}
 
+	err = script.CheckPlatform()
+	if err != nil {
+		return fmt.Errorf("script '%s' can not run: %w", scriptName, err)
+	}
+
+	err = script.CheckTemplates()
+	if err != nil {
+		return fmt.Errorf("error rendering script '%s': %w", scriptName, err)
+	}
+
+	// Parameters (`--name=value`)
+	params, args, err := script.Params.Parse(args, a.invokeDir)
+	if err != nil {
+		return fmt.Errorf("error parsing parameters of script '%s': %w", scriptName, err)
+	}
+
+	// Web dashboard (`--web`). Started before the control server, which notifies the parent process of a daemon that the session is
+	// running
+	webServer, err := a.startWebServer(runtime)
+	if err != nil {
+		return err
+	}
+	if webServer != nil {
+		defer webServer.Close()
+	}
+
+	// Metrics (`--metrics`)
+	metricsServer, err := a.startMetricsServer(runtime)
+	if err != nil {
+		return err
+	}
+	if metricsServer != nil {
+		defer metricsServer.Close()
+	}
+
+	// Control server (`falcula ctl`). Closed before the runtime
+	controlServer, err := a.startControlServer(runtime, "script "+scriptName)
+	if err != nil {
+		return err
+	}
+	if controlServer != nil {
+		defer controlServer.Close()
+	}
+
+	// modtui does not closes the TUI when it is closed. The TUI is persistent across runs. Need to close it manually
+	defer modtui.ClosePersistentTui()
+
 	// Changes to script directory
 	err = os.Chdir(script.Cwd)
 	if err != nil {
@@ -53,8 +119,10 @@ func (a *App) RunScript(scriptName string, args ...string) error {
 			cmd = process.CreateCmd(true, script.Command.String, cmdArgs...)
 		}
 		a.configureCmd(cmd, script.Project.Folder)
+		cmd.Env = append(cmd.Env, script.GetEnv()...)
+		cmd.Env = append(cmd.Env, script.Params.GetEnv(params)...)
 
-		err := cmd.Run()
+		err := a.runCmd(runtime, "script "+scriptName, cmd)
 		if err != nil {
 			return fmt.Errorf("error running command: %w", err)
 		}
@@ -64,6 +132,8 @@ func (a *App) RunScript(scriptName string, args ...string) error {
 			Runtime: runtime,
 			Code:    script.Lua,
 			Args:    args,
+			Params:  params,
+			Name:    "script " + scriptName,
 		}
 
 		err := a.runLuaCode(config)
@@ -74,8 +144,10 @@ func (a *App) RunScript(scriptName string, args ...string) error {
 	} else if script.File != "" {
 		cmd := process.CreateCmd(false, script.File, args...)
 		a.configureCmd(cmd, script.Project.Folder)
+		cmd.Env = append(cmd.Env, script.GetEnv()...)
+		cmd.Env = append(cmd.Env, script.Params.GetEnv(params)...)
 
-		err := cmd.Run()
+		err := a.runCmd(runtime, "script "+scriptName, cmd)
 		if err != nil {
 			return fmt.Errorf("error running command: %w", err)
 		}
@@ -85,6 +157,8 @@ func (a *App) RunScript(scriptName string, args ...string) error {
 			Runtime: runtime,
 			File:    script.LuaFile,
 			Args:    args,
+			Params:  params,
+			Name:    "script " + scriptName,
 		}
 
 		err := a.runLuaFile(config)
@@ -0,0 +1,86 @@
+package enhanced
+
+import (
+	"maps"
+	"time"
+)
+
+// SetMetadata sets a metadata of the service (e.g. a port discovered in its logs). Thread-safe
+func (e *EnhancedService) SetMetadata(key string, value string) {
+	e.metadataMutex.Lock()
+	defer e.metadataMutex.Unlock()
+
+	e.metadata[key] = value
+}
+
+// GetMetadata returns a metadata of the service and whether it exists. Thread-safe
+func (e *EnhancedService) GetMetadata(key string) (string, bool) {
+	e.metadataMutex.Lock()
+	defer e.metadataMutex.Unlock()
+
+	value, ok := e.metadata[key]
+	return value, ok
+}
+
+// GetAllMetadata returns a copy of all metadata of the service. Thread-safe
+func (e *EnhancedService) GetAllMetadata() map[string]string {
+	e.metadataMutex.Lock()
+	defer e.metadataMutex.Unlock()
+
+	return maps.Clone(e.metadata)
+}
+
+// SetReady marks the running service as ready (e.g. it logged that it is listening). Different from the `status.Ready` status, that means
+// the service is prepared. The mark is cleared when the service starts again. Thread-safe
+func (e *EnhancedService) SetReady() {
+	e.metadataMutex.Lock()
+	if e.ready {
+		e.metadataMutex.Unlock()
+		return
+	}
+
+	e.ready = true
+	close(e.readyChan)
+	e.metadataMutex.Unlock()
+
+	e.callbacks.OnServiceStatusChanged(e)
+}
+
+// IsReady returns true if the running service is marked as ready. Thread-safe
+func (e *EnhancedService) IsReady() bool {
+	e.metadataMutex.Lock()
+	defer e.metadataMutex.Unlock()
+
+	return e.ready
+}
+
+// WaitReady waits until the service is marked as ready. Returns false if the timeout expires first. Waits forever if the timeout is not
+// positive
+func (e *EnhancedService) WaitReady(timeout time.Duration) bool {
+	e.metadataMutex.Lock()
+	readyChan := e.readyChan
+	e.metadataMutex.Unlock()
+
+	if timeout <= 0 {
+		<-readyChan
+		return true
+	}
+
+	select {
+	case <-readyChan:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// resetReady clears the ready mark
+func (e *EnhancedService) resetReady() {
+	e.metadataMutex.Lock()
+	defer e.metadataMutex.Unlock()
+
+	if e.ready {
+		e.ready = false
+		e.readyChan = make(chan struct{})
+	}
+}
@@ -4,6 +4,7 @@ package enhanced
 import (
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/LucasAVasco/falcula/process"
 	"github.com/LucasAVasco/falcula/service/empty"
@@ -29,6 +30,15 @@ type EnhancedService struct {
 	status    status.Status
 	callbacks *Callbacks
 	step      iface.Step
+
+	metadataMutex sync.Mutex        // Protects the metadata and the ready mark
+	metadata      map[string]string // Information about the service set by the user (e.g. discovered port)
+	ready         bool              // The running service is ready (see `SetReady`)
+	readyChan     chan struct{}     // Closed when the service is marked as ready
+
+	statsMutex sync.Mutex // Protects the statistics and the resource usage
+	stats      Stats
+	usage      []Usage // Last resource usage samples (see `RecordUsage`)
 }
 
 // NewEnhancedService returns a new EnhancedService. The callbacks parameter is optional
@@ -37,6 +47,8 @@ func NewEnhancedService(svc iface.Service, callbacks *Callbacks) *EnhancedServic
 		svc:       svc,
 		status:    status.None,
 		callbacks: fillCallbacksWithDefaults(callbacks),
+		metadata:  make(map[string]string),
+		readyChan: make(chan struct{}),
 	}
 
 	if e.svc.GetOpts().StartDisabled {
@@ -111,6 +123,8 @@ func (e *EnhancedService) StartPrepare() error {
 	e.setStatus(status.Preparing)
 	var err error
 	e.step, err = e.svc.Prepare(func(exitInfo *iface.ExitInfo, err error) {
+		e.recordExit(false, exitInfo, err)
+
 		if exitInfoHasError(exitInfo) || err != nil {
 			e.setErrorStatus()
 		} else if exitInfo.Stopped {
@@ -233,9 +247,13 @@ func (e *EnhancedService) Start() error {
 	}
 
 	// Starts the main step
+	e.resetReady()
+	e.recordStart()
 	e.setStatus(status.Running)
 	var err error
 	e.step, err = e.svc.Start(func(exitInfo *iface.ExitInfo, err error) {
+		e.recordExit(true, exitInfo, err)
+
 		if exitInfoHasError(exitInfo) || err != nil {
 			e.setErrorStatus()
 		} else if exitInfo.Stopped {
@@ -427,6 +445,8 @@ func (e *EnhancedService) Restart(force bool) (*iface.ExitInfo, error) {
 	if e.status == status.Disabled {
 		return &process.ExitInfo{}, nil
 	}
+	e.recordRestart()
+
 	// Resets the service
 	exitInfo, err := e.Reset(force)
 	if err != nil {
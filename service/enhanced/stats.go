@@ -0,0 +1,86 @@
+package enhanced
+
+import (
+	"time"
+
+	"github.com/LucasAVasco/falcula/service/iface"
+	"github.com/LucasAVasco/falcula/service/status"
+)
+
+// Stats are the statistics of the executions of a service
+type Stats struct {
+	Restarts     uint            // Number of times the service was restarted
+	StartTime    time.Time       // Start of the last main step. Zero if the service never started
+	EndTime      time.Time       // End of the last main step. Zero if it is running or never started
+	LastExitInfo *iface.ExitInfo // Exit information of the last step (prepare or main) that ended. Nil if no step ended
+	LastError    error           // Error of the last step that ended. Nil if it has no error
+}
+
+// GetStats returns a copy of the statistics of the service. Thread-safe
+func (e *EnhancedService) GetStats() Stats {
+	e.statsMutex.Lock()
+	defer e.statsMutex.Unlock()
+
+	return e.stats
+}
+
+// GetUptime returns for how long the main step is running. Returns the duration of the last execution if it is not running. Thread-safe
+func (e *EnhancedService) GetUptime() time.Duration {
+	stats := e.GetStats()
+
+	switch {
+	case stats.StartTime.IsZero():
+		return 0
+	case stats.EndTime.IsZero():
+		return time.Since(stats.StartTime)
+	default:
+		return stats.EndTime.Sub(stats.StartTime)
+	}
+}
+
+// GetPids returns the PIDs of the running processes of the service. Returns nil if the service is not running or does not run system
+// processes (see `iface.ProcessStep`)
+func (e *EnhancedService) GetPids() []int {
+	if e.status != status.Running {
+		return nil
+	}
+
+	step, ok := e.step.(iface.ProcessStep)
+	if !ok {
+		return nil
+	}
+
+	return step.GetPids()
+}
+
+// recordStart records the start of the main step
+func (e *EnhancedService) recordStart() {
+	e.statsMutex.Lock()
+	defer e.statsMutex.Unlock()
+
+	e.stats.StartTime = time.Now()
+	e.stats.EndTime = time.Time{}
+}
+
+// recordExit records the end of a step. The main step also records its end time
+func (e *EnhancedService) recordExit(mainStep bool, exitInfo *iface.ExitInfo, err error) {
+	e.statsMutex.Lock()
+	defer e.statsMutex.Unlock()
+
+	if mainStep {
+		e.stats.EndTime = time.Now()
+	}
+
+	if exitInfo != nil {
+		e.stats.LastExitInfo = exitInfo
+	}
+	e.stats.LastError = err
+}
+
+// recordRestart increments the number of restarts
+func (e *EnhancedService) recordRestart() {
+	e.statsMutex.Lock()
+	defer e.statsMutex.Unlock()
+
+	e.stats.Restarts++
+}
@@ -0,0 +1,57 @@
+package enhanced
+
+import (
+	"slices"
+	"time"
+)
+
+// UsageHistorySize is the number of resource usage samples kept by a service
+const UsageHistorySize = 30
+
+// Usage is a sample of the resource usage of the processes of a service (and their children)
+type Usage struct {
+	Time       time.Time `json:"time"`
+	CPUPercent float64   `json:"cpu_percent"` // CPU usage since the last sample, in percent of one core
+	RSS        uint64    `json:"rss"`         // Resident memory in bytes
+	FDs        int       `json:"fds"`         // Open file descriptors
+	Threads    int       `json:"threads"`
+}
+
+// RecordUsage records a sample of the resource usage. Only the last `UsageHistorySize` samples are kept. Thread-safe
+func (e *EnhancedService) RecordUsage(usage Usage) {
+	e.statsMutex.Lock()
+	defer e.statsMutex.Unlock()
+
+	e.usage = append(e.usage, usage)
+	if len(e.usage) > UsageHistorySize {
+		e.usage = slices.Delete(e.usage, 0, len(e.usage)-UsageHistorySize)
+	}
+}
+
+// ResetUsage forgets the resource usage samples (e.g. the service stopped). Thread-safe
+func (e *EnhancedService) ResetUsage() {
+	e.statsMutex.Lock()
+	defer e.statsMutex.Unlock()
+
+	e.usage = nil
+}
+
+// GetUsage returns the last resource usage sample. Returns false if there is no sample. Thread-safe
+func (e *EnhancedService) GetUsage() (Usage, bool) {
+	e.statsMutex.Lock()
+	defer e.statsMutex.Unlock()
+
+	if len(e.usage) == 0 {
+		return Usage{}, false
+	}
+
+	return e.usage[len(e.usage)-1], true
+}
+
+// GetUsageHistory returns a copy of the resource usage samples, from the oldest to the newest. Thread-safe
+func (e *EnhancedService) GetUsageHistory() []Usage {
+	e.statsMutex.Lock()
+	defer e.statsMutex.Unlock()
+
+	return slices.Clone(e.usage)
+}
@@ -19,9 +19,54 @@ type Step interface {
 	Abort(force bool) (*ExitInfo, error)
 }
 
+// ProcessStep is a Step that runs system processes. Optional, used to get the resource usage of the service
+type ProcessStep interface {
+	Step
+	GetPids() []int // PIDs of the running processes of the step
+}
+
+// Actions of an output trigger that are not Lua functions
+const (
+	OutputActionReady   = "ready"   // Marks the service as ready
+	OutputActionRestart = "restart" // Restarts the service
+	OutputActionStop    = "stop"    // Stops the service
+)
+
+// OutputTrigger runs an action when a log line of the service matches a pattern
+type OutputTrigger struct {
+	Pattern  string `lua:"pattern"`  // Lua pattern matched against each log line (e.g. 'Listening on :(%d+)')
+	Action   any    `lua:"action"`   // One of the `OutputAction*` constants or a Lua function called with the service and the captures
+	Metadata string `lua:"metadata"` // If not empty, the first capture is saved in the service metadata with this key
+}
+
+// Resources of a resource threshold
+const (
+	ResourceCPU     = "cpu"     // CPU usage in percent of one core
+	ResourceMemory  = "memory"  // Resident memory in bytes
+	ResourceFDs     = "fds"     // Open file descriptors
+	ResourceThreads = "threads" // Threads
+)
+
+// Actions of a resource threshold
+const (
+	ThresholdActionWarn    = "warn"    // Logs a warning
+	ThresholdActionRestart = "restart" // Restarts the service
+)
+
+// ResourceThreshold runs an action when the resource usage of the service (sum of its processes and their children) exceeds a maximum
+type ResourceThreshold struct {
+	Resource string `lua:"resource"` // One of the `Resource*` constants
+	Max      any    `lua:"max"`      // Maximum value. The memory can also be a size with unit (e.g. '512M')
+	Samples  int    `lua:"samples"`  // Number of consecutive samples above the maximum required to run the action. Default: 1
+	Action   string `lua:"action"`   // One of the `ThresholdAction*` constants. Default: 'warn'
+}
+
 // Opts is the service options
 type Opts struct {
-	StartDisabled bool `lua:"start_disabled"` // The service will be disabled by default (it must be enabled before it can be used)
+	StartDisabled bool                 `lua:"start_disabled"` // The service will be disabled by default (it must be enabled before it can be used)
+	LogFormat     string               `lua:"log_format"`     // Format of the service logs ('json', 'logfmt' or 'regex:<pattern>'). Not parsed if empty
+	OnOutput      []*OutputTrigger     `lua:"on_output"`      // Triggers evaluated on each log line of the service
+	Thresholds    []*ResourceThreshold `lua:"thresholds"`     // Actions when the resource usage of the service is too high
 }
 
 // Service represents a service managed by this application. All services must implement this interface
@@ -4,6 +4,7 @@ package manager
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
 
 	"github.com/LucasAVasco/falcula/service/enhanced"
@@ -100,10 +101,27 @@ func (m *Manager) RemoveServices(services []*enhanced.EnhancedService, force boo
 	return nil
 }
 
-// GetServices returns the managed enhanced services. You should not modify the returned slice, only use its services. If you want to add or
-// remove services, use the AddService and RemoveService methods
+// GetServices returns a copy of the managed enhanced services list. If you want to add or remove services, use the AddService and
+// RemoveService methods
 func (m *Manager) GetServices() []*enhanced.EnhancedService {
-	return m.services
+	m.serviceListMutex.Lock()
+	defer m.serviceListMutex.Unlock()
+
+	return slices.Clone(m.services)
+}
+
+// GetServiceByName returns the managed enhanced service with the provided name. Returns nil if it is not found
+func (m *Manager) GetServiceByName(name string) *enhanced.EnhancedService {
+	m.serviceListMutex.Lock()
+	defer m.serviceListMutex.Unlock()
+
+	for _, svc := range m.services {
+		if svc.GetName() == name {
+			return svc
+		}
+	}
+
+	return nil
 }
 
 // routineForEachService executes a callback for each service in a goroutine and returns a waiter with the results of the callbacks
@@ -2,10 +2,12 @@ package falcula
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
 	"strings"
 
+	"github.com/LucasAVasco/falcula/fingerprint"
 	"github.com/LucasAVasco/falcula/lua/luaruntime"
 	"github.com/LucasAVasco/falcula/lua/modules/modtui"
 	"github.com/LucasAVasco/falcula/process"
@@ -13,34 +15,141 @@ import (
 	lua "github.com/yuin/gopher-lua"
 )
 
-// GetTaskList returns all the tasks in the current project and its children projects
-func (a *App) GetTaskList() (map[string]*project.Task, error) {
-	return a.project.GetAllTasks()
+// GetTaskList returns all the tasks in the current project and its children projects. Hides the tasks that can not run on the current
+// platform unless all is true
+func (a *App) GetTaskList(all bool) (map[string]*project.Task, error) {
+	tasks, err := a.project.GetAllTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	if !all {
+		maps.DeleteFunc(tasks, func(name string, task *project.Task) bool {
+			return task.CheckPlatform() != nil
+		})
+	}
+
+	return tasks, nil
+}
+
+// GetTaskParams returns the parameters of a task. The task ID can have sub-tasks (they have the parameters of the task)
+func (a *App) GetTaskParams(taskId string) (project.Params, error) {
+	taskName, _, _ := strings.Cut(taskId, ".")
+	task, err := a.project.GetTaskByName(taskName)
+	if err != nil {
+		return nil, err
+	}
+
+	return task.Params, nil
 }
 
 // RunTask runs a task of the current project with the given arguments. The taskName can be the name of a named task or the path to
 // a task
 func (a *App) RunTask(taskId string, args ...string) error {
-	taskName, subTask, _ := strings.Cut(taskId, ".")
+	taskName, _, _ := strings.Cut(taskId, ".")
 
 	// Lua runtime
-	runtime, err := luaruntime.New()
+	runtime, err := a.newRuntime()
 	if err != nil {
 		return fmt.Errorf("error creating runtime: %w", err)
 	}
 	defer runtime.Close()
 
-	// modtui does not closes the TUI when it is closed. The TUI is persistent across runs. Need to close it manually
-	defer modtui.ClosePersistentTui()
-
 	// Task to run
 	task, err := a.project.GetTaskByName(taskName)
 	if err != nil {
 		return fmt.Errorf("error getting task to run: %w", err)
 	}
 
+	err = task.CheckPlatform()
+	if err != nil {
+		return fmt.Errorf("task '%s' can not run: %w", taskName, err)
+	}
+
+	err = task.CheckTemplates()
+	if err != nil {
+		return fmt.Errorf("error rendering task '%s': %w", taskName, err)
+	}
+
+	// Web dashboard (`--web`). Started before the control server, which notifies the parent process of a daemon that the session is
+	// running
+	webServer, err := a.startWebServer(runtime)
+	if err != nil {
+		return err
+	}
+	if webServer != nil {
+		defer webServer.Close()
+	}
+
+	// Metrics (`--metrics`)
+	metricsServer, err := a.startMetricsServer(runtime)
+	if err != nil {
+		return err
+	}
+	if metricsServer != nil {
+		defer metricsServer.Close()
+	}
+
+	// Control server (`falcula ctl`). Closed before the runtime
+	controlServer, err := a.startControlServer(runtime, "task "+taskId)
+	if err != nil {
+		return err
+	}
+	if controlServer != nil {
+		defer controlServer.Close()
+	}
+
+	// Parameters (`--name=value`)
+	params, positionalArgs, err := task.Params.Parse(args, a.invokeDir)
+	if err != nil {
+		return fmt.Errorf("error parsing parameters of task '%s': %w", taskId, err)
+	}
+
+	// Dependencies (`deps`)
+	if !a.noDeps {
+		err = a.runTaskDeps(runtime, task, taskId)
+		if err != nil {
+			return fmt.Errorf("error running dependencies of task '%s': %w", taskId, err)
+		}
+	}
+
+	// Skips the task if nothing changed since its last successful run (`sources` and `generates`)
+	taskFingerprint, err := getTaskFingerprint(task, getTaskLocalId(taskId), args)
+	if err != nil {
+		return fmt.Errorf("error computing fingerprint of task '%s': %w", taskId, err)
+	}
+	if taskFingerprint != nil && taskFingerprint.status == fingerprint.StatusUpToDate && !a.force {
+		runtime.Logger.LogDebug(fmt.Sprintf("Task '%s' is up to date\n", taskId))
+		return nil
+	}
+
+	// modtui does not closes the TUI when it is closed. The TUI is persistent across runs. Need to close it manually
+	defer modtui.ClosePersistentTui()
+
+	err = a.runTaskAction(runtime, task, taskId, params, positionalArgs)
+	if err != nil {
+		return err
+	}
+
+	// A dry run does not change the generated files
+	if taskFingerprint != nil && a.dryRunPlan == nil {
+		err = taskFingerprint.save(task)
+		if err != nil {
+			return fmt.Errorf("error saving fingerprint of task '%s': %w", taskId, err)
+		}
+	}
+
+	return nil
+}
+
+// runTaskAction runs the action of a task (command, shell file, Lua code or Lua file)
+func (a *App) runTaskAction(runtime *luaruntime.Runtime, task *project.Task, taskId string, params project.ParamValues,
+	args []string,
+) error {
+	taskName, subTask, _ := strings.Cut(taskId, ".")
+
 	// Changes to task directory
-	err = os.Chdir(task.Cwd)
+	err := os.Chdir(task.Cwd)
 	if err != nil {
 		return fmt.Errorf("error changing to task working directory: %w", err)
 	}
@@ -50,16 +159,16 @@ func (a *App) RunTask(taskId string, args ...string) error {
 			cmdArgs := task.Command.List[1:]
 			cmdArgs = append(cmdArgs, args...)
 			cmd := process.CreateCmd(false, task.Command.List[0], cmdArgs...)
-			a.configureTaskCmd(cmd, task, taskId)
+			a.configureTaskCmd(cmd, task, taskId, params)
 
-			err := cmd.Run()
+			err := a.runCmd(runtime, "task "+taskId, cmd)
 			if err != nil {
 				return fmt.Errorf("error running task command: %w", err)
 			}
 
 		} else if task.Command.String != "" {
 			code := task.Command.String
-			err := a.runTaskShellScript(task, taskId, code, args)
+			err := a.runTaskShellScript(runtime, task, taskId, params, code, args)
 			if err != nil {
 				return fmt.Errorf("error running task shell script: %w", err)
 			}
@@ -73,6 +182,8 @@ func (a *App) RunTask(taskId string, args ...string) error {
 			Runtime: runtime,
 			Code:    task.Lua,
 			Args:    args,
+			Params:  params,
+			Name:    "task " + taskId,
 			AfterRun: func(runtime *luaruntime.Runtime) error {
 				err = handleReturnedLuaTask(runtime.GetLuaState(), subTask, args)
 				if err != nil {
@@ -90,7 +201,7 @@ func (a *App) RunTask(taskId string, args ...string) error {
 	} else if task.File != "" {
 		code := "source " + task.File
 
-		err := a.runTaskShellScript(task, taskId, code, args)
+		err := a.runTaskShellScript(runtime, task, taskId, params, code, args)
 		if err != nil {
 			return fmt.Errorf("error running task shell script: %w", err)
 		}
@@ -100,6 +211,8 @@ func (a *App) RunTask(taskId string, args ...string) error {
 			Runtime: runtime,
 			Code:    task.LuaFile,
 			Args:    args,
+			Params:  params,
+			Name:    "task " + taskId,
 			AfterRun: func(runtime *luaruntime.Runtime) error {
 				err = handleReturnedLuaTask(runtime.GetLuaState(), subTask, args)
 				if err != nil {
@@ -122,8 +235,10 @@ func (a *App) RunTask(taskId string, args ...string) error {
 }
 
 // configureTaskCmd configures a task execution command
-func (a *App) configureTaskCmd(cmd *exec.Cmd, task *project.Task, taskId string) {
+func (a *App) configureTaskCmd(cmd *exec.Cmd, task *project.Task, taskId string, params project.ParamValues) {
 	a.configureCmd(cmd, task.Project.Folder)
+	cmd.Env = append(cmd.Env, task.GetEnv()...)
+	cmd.Env = append(cmd.Env, task.Params.GetEnv(params)...)
 
 	taskName, subTask, _ := strings.Cut(taskId, ".")
 	cmd.Env = append(cmd.Env, "FALCULA_TASK_ID="+taskId)
@@ -132,7 +247,9 @@ func (a *App) configureTaskCmd(cmd *exec.Cmd, task *project.Task, taskId string)
 }
 
 // runTaskShellScript runs a shell script code with the given arguments and executes a subtask of it
-func (a *App) runTaskShellScript(task *project.Task, taskId, code string, args []string) error {
+func (a *App) runTaskShellScript(runtime *luaruntime.Runtime, task *project.Task, taskId string, params project.ParamValues, code string,
+	args []string,
+) error {
 	_, subTask, _ := strings.Cut(taskId, ".")
 
 	// Executes the subtask after the provided code
@@ -146,9 +263,9 @@ func (a *App) runTaskShellScript(task *project.Task, taskId, code string, args [
 
 	// Runs the command
 	cmd := process.CreateCmd(true, code, cmdArgs...)
-	a.configureTaskCmd(cmd, task, taskId)
+	a.configureTaskCmd(cmd, task, taskId, params)
 
-	err := cmd.Run()
+	err := a.runCmd(runtime, "task "+taskId, cmd)
 	if err != nil {
 		return fmt.Errorf("error running command: %w", err)
 	}
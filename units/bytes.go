@@ -0,0 +1,57 @@
+// Package units parses and formats quantities with units (e.g. sizes in bytes)
+package units
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// byteSuffixes are the supported size suffixes and their multipliers (base 1024)
+var byteSuffixes = []struct {
+	suffix     string
+	multiplier int64
+}{
+	{"T", 1 << 40},
+	{"G", 1 << 30},
+	{"M", 1 << 20},
+	{"K", 1 << 10},
+	{"B", 1},
+}
+
+// ParseBytes parses a size in bytes. Supports the suffixes 'K', 'M', 'G' and 'T' (base 1024, case insensitive, with an optional trailing
+// 'B' or 'iB'). Examples: '512', '64K', '2G', '1.5GiB'
+func ParseBytes(size string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(size))
+	value = strings.TrimSuffix(value, "IB")
+	if len(value) > 1 {
+		value = strings.TrimSuffix(value, "B")
+	}
+
+	multiplier := int64(1)
+	for _, suffix := range byteSuffixes {
+		if strings.HasSuffix(value, suffix.suffix) {
+			value = strings.TrimSuffix(value, suffix.suffix)
+			multiplier = suffix.multiplier
+			break
+		}
+	}
+
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid size '%s'", size)
+	}
+
+	return int64(number * float64(multiplier)), nil
+}
+
+// FormatBytes formats a size in bytes with the biggest suffix that keeps the value greater or equal to one. Example: 1536 -> '1.5K'
+func FormatBytes(size int64) string {
+	for _, suffix := range byteSuffixes {
+		if suffix.multiplier > 1 && size >= suffix.multiplier {
+			return strconv.FormatFloat(float64(size)/float64(suffix.multiplier), 'f', 1, 64) + suffix.suffix
+		}
+	}
+
+	return strconv.FormatInt(size, 10) + "B"
+}
@@ -0,0 +1,115 @@
+package falcula
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"slices"
+
+	"github.com/LucasAVasco/falcula/fingerprint"
+	"github.com/LucasAVasco/falcula/project"
+)
+
+// taskFingerprint is the current fingerprint of a task and its status
+type taskFingerprint struct {
+	file    string // Fingerprint of the last successful run
+	current *fingerprint.Fingerprint
+	status  fingerprint.Status
+}
+
+// TaskStatus is the status of a task fingerprint (see `App.GetTaskStatuses`)
+type TaskStatus struct {
+	Name   string
+	Status fingerprint.Status // Empty if the task has no sources (always runs)
+}
+
+// getTaskFingerprint computes the fingerprint of the sources and generated files of a task and compares it to its last successful run.
+// The definition of the task and its arguments are part of the fingerprint. Returns nil if the task has no sources (it always runs)
+func getTaskFingerprint(task *project.Task, localId string, args []string) (*taskFingerprint, error) {
+	if len(task.Sources) == 0 {
+		return nil, nil
+	}
+
+	definition, err := json.Marshal(map[string]any{
+		"id":        localId,
+		"args":      append([]string{}, args...),
+		"cwd":       task.Cwd,
+		"command":   task.Command,
+		"lua":       task.Lua,
+		"file":      task.File,
+		"lua_file":  task.LuaFile,
+		"env":       task.GetEnv(),
+		"sources":   task.Sources,
+		"generates": task.Generates,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding task definition: %w", err)
+	}
+
+	current, err := fingerprint.Compute(task.Cwd, task.Sources, task.Generates, string(definition))
+	if err != nil {
+		return nil, err
+	}
+
+	file := filepath.Join(task.Project.Folder, fingerprint.CacheDirName, "tasks", url.PathEscape(localId)+".json")
+	last, err := fingerprint.Read(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return &taskFingerprint{
+		file:    file,
+		current: current,
+		status:  current.Compare(last),
+	}, nil
+}
+
+// save saves the fingerprint after a successful run of the task. The generated files are hashed again, because the task changed them
+func (t *taskFingerprint) save(task *project.Task) error {
+	generated, err := fingerprint.Compute(task.Cwd, nil, task.Generates, "")
+	if err != nil {
+		return err
+	}
+
+	t.current.Generates = generated.Generates
+	return t.current.Write(t.file)
+}
+
+// GetTaskStatuses returns the status of the fingerprint of the tasks of the current project and its child projects (all tasks if no name
+// is provided). The status of the tasks that receive arguments is computed without arguments
+func (a *App) GetTaskStatuses(names ...string) ([]*TaskStatus, error) {
+	tasks, err := a.project.GetAllTasks()
+	if err != nil {
+		return nil, fmt.Errorf("error getting tasks: %w", err)
+	}
+
+	if len(names) == 0 {
+		for name := range tasks {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+	}
+
+	statuses := []*TaskStatus{}
+	for _, name := range names {
+		task, err := a.project.GetTaskByName(name)
+		if err != nil {
+			return nil, fmt.Errorf("error getting task '%s': %w", name, err)
+		}
+
+		taskFingerprint, err := getTaskFingerprint(task, getTaskLocalId(name), nil)
+		if err != nil {
+			return nil, fmt.Errorf("error computing fingerprint of task '%s': %w", name, err)
+		}
+
+		status := TaskStatus{Name: name}
+		if taskFingerprint != nil {
+			status.Status = taskFingerprint.status
+		}
+
+		statuses = append(statuses, &status)
+	}
+
+	return statuses, nil
+}
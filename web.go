@@ -0,0 +1,28 @@
+package falcula
+
+import (
+	"fmt"
+
+	"github.com/LucasAVasco/falcula/lua/luaruntime"
+	"github.com/LucasAVasco/falcula/web"
+)
+
+// startWebServer starts the web dashboard of the session. Does nothing and returns nil if the dashboard is disabled
+func (a *App) startWebServer(runtime *luaruntime.Runtime) (*web.Server, error) {
+	if a.webAddr == "" {
+		return nil, nil
+	}
+
+	server, err := web.New(&web.Options{
+		Address: a.webAddr,
+		Runtime: runtime,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error starting web dashboard: %w", err)
+	}
+
+	a.webServer = server
+	runtime.Logger.LogDebug(fmt.Sprintf("Web dashboard listening on %s\n", server.GetURL()))
+
+	return server, nil
+}
@@ -0,0 +1,137 @@
+package web
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/LucasAVasco/falcula/control"
+	"github.com/LucasAVasco/falcula/logsink"
+)
+
+// subscriberQueueSize is the number of events that can wait to be sent to a browser. New events are dropped if the queue is full
+const subscriberQueueSize = 1024
+
+// Names of the Server-Sent Events
+const (
+	eventServices = "services" // All managers and their services. Data: []control.ManagerInfo
+	eventStatus   = "status"   // Status of a service. Data: control.ServiceInfo
+	eventLog      = "log"      // Service log line. Data: control.LogEntry
+)
+
+// event is a Server-Sent Event
+type event struct {
+	name string
+	data []byte // JSON encoded data
+}
+
+func newEvent(name string, data any) (event, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return event{}, err
+	}
+
+	return event{name: name, data: encoded}, nil
+}
+
+// broker is a log sink that keeps the last service logs and sends them, and the other events, to the browsers
+type broker struct {
+	mutex       sync.Mutex
+	logs        []event // Last log events. Sent to the new subscribers
+	logsSize    int
+	subscribers map[chan event]struct{}
+	closed      bool
+}
+
+func newBroker(logsSize int) *broker {
+	if logsSize <= 0 {
+		logsSize = logsink.DefaultRingSize
+	}
+
+	return &broker{
+		logs:        make([]event, 0, logsSize),
+		logsSize:    logsSize,
+		subscribers: make(map[chan event]struct{}),
+	}
+}
+
+func (b *broker) Write(entry *logsink.Entry) error {
+	logEvent, err := newEvent(eventLog, &control.LogEntry{
+		Time:    entry.Time,
+		Service: entry.Service,
+		Stream:  entry.Stream,
+		Level:   entry.Level,
+		Message: entry.Message,
+	})
+	if err != nil {
+		return err
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if len(b.logs) == b.logsSize {
+		b.logs = append(b.logs[:0], b.logs[1:]...)
+	}
+	b.logs = append(b.logs, logEvent)
+
+	b.publishWithoutLock(logEvent)
+	return nil
+}
+
+// publish sends an event to all subscribers
+func (b *broker) publish(ev event) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.publishWithoutLock(ev)
+}
+
+func (b *broker) publishWithoutLock(ev event) {
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- ev:
+		default: // Slow browser
+		}
+	}
+}
+
+// Close closes the channels of all subscribers
+func (b *broker) Close() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for subscriber := range b.subscribers {
+		close(subscriber)
+	}
+	clear(b.subscribers)
+	b.closed = true
+
+	return nil
+}
+
+// subscribe returns the last log events and a channel that receives the new events. The channel is closed when the broker is closed. The
+// subscriber must call `unsubscribe` when done
+func (b *broker) subscribe() ([]event, chan event) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	subscriber := make(chan event, subscriberQueueSize)
+	if b.closed {
+		close(subscriber)
+	} else {
+		b.subscribers[subscriber] = struct{}{}
+	}
+
+	return append([]event(nil), b.logs...), subscriber
+}
+
+// unsubscribe removes a subscriber and closes its channel
+func (b *broker) unsubscribe(subscriber chan event) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if _, ok := b.subscribers[subscriber]; ok {
+		delete(b.subscribers, subscriber)
+		close(subscriber)
+	}
+}
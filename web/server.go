@@ -0,0 +1,287 @@
+// Package web implements a local web dashboard to see and control the services of a session. It is an alternative to the TUI: shows the
+// managers and services, their status, buttons to start, restart and stop them, and the service logs (streamed with Server-Sent Events)
+package web
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/LucasAVasco/falcula/control"
+	"github.com/LucasAVasco/falcula/lua/luaruntime"
+	"github.com/LucasAVasco/falcula/lua/modules/modmanager"
+	"github.com/LucasAVasco/falcula/netaddr"
+	"github.com/LucasAVasco/falcula/service/enhanced"
+	"github.com/LucasAVasco/falcula/service/manager"
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+// closeTimeout is the maximum time to wait for the pending requests when closing the server
+const closeTimeout = time.Second
+
+// actionHeader must be sent by the requests that run actions. Browsers do not send custom headers in cross-origin requests without asking
+// the server first (CORS preflight), so other websites can not control the services
+const actionHeader = "X-Falcula-Action"
+
+// actions are the actions that can be run on a service
+var actions = map[string]bool{
+	control.MethodStart:   true,
+	control.MethodStop:    true,
+	control.MethodRestart: true,
+	control.MethodEnable:  true,
+	control.MethodDisable: true,
+}
+
+// Options is the server options
+type Options struct {
+	Address       string              // Address to listen on (e.g. ':7777', '0.0.0.0:7777'). A port without host listens on 127.0.0.1. Required
+	Runtime       *luaruntime.Runtime // Runtime with the managers and the logger. Required
+	LogBufferSize int                 // Number of log lines kept to send to new browsers. Uses `logsink.DefaultRingSize` if 0
+}
+
+// Server is the web dashboard server of a session
+type Server struct {
+	opts       Options
+	listener   net.Listener
+	httpServer *http.Server
+	broker     *broker // Service logs and service changes sent to the browsers
+	done       chan struct{}
+}
+
+// New creates a server and starts listening on its address
+func New(opts *Options) (*Server, error) {
+	address, err := netaddr.GetListenAddress(opts.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("error listening on address '%s': %w", address, err)
+	}
+
+	s := &Server{
+		opts:     *opts,
+		listener: listener,
+		broker:   newBroker(opts.LogBufferSize),
+		done:     make(chan struct{}),
+	}
+
+	static, err := fs.Sub(staticFiles, "static")
+	if err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("error opening static files: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /", http.FileServerFS(static))
+	mux.HandleFunc("GET /api/services", s.handleServices)
+	mux.HandleFunc("POST /api/services/{manager}/{service}/{action}", s.handleAction)
+	mux.HandleFunc("GET /api/events", s.handleEvents)
+	configuredHost, _, _ := net.SplitHostPort(address)
+	s.httpServer = &http.Server{Handler: s.checkHost(mux, configuredHost)}
+
+	err = s.opts.Runtime.Logger.AddSink("", s.broker)
+	if err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("error adding log sink of web server: %w", err)
+	}
+
+	go func() {
+		defer close(s.done)
+		s.httpServer.Serve(listener)
+	}()
+
+	return s, nil
+}
+
+// checkHost rejects the requests whose 'Host' header is not a loopback name or the host of the server address (the configured host or the
+// IP address it listens on). Protects against DNS rebinding: a website that resolves its domain to the local machine can not read the
+// logs nor control the services
+func (s *Server) checkHost(handler http.Handler, configuredHost string) http.Handler {
+	serverHost, _, _ := net.SplitHostPort(s.listener.Addr().String())
+	serverIP := net.ParseIP(serverHost)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host // No port
+		}
+		host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+
+		allowed := host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.EqualFold(host, configuredHost)
+		if ip := net.ParseIP(host); ip != nil {
+			// A website can not use an IP address in the 'Host' header, so all of them are allowed if the server listens on all addresses
+			allowed = ip.IsLoopback() || ip.Equal(serverIP) || serverIP.IsUnspecified()
+		}
+
+		if !allowed {
+			writeError(w, http.StatusForbidden, fmt.Errorf("invalid host '%s'", r.Host))
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// GetURL returns the URL of the dashboard
+func (s *Server) GetURL() string {
+	addr := s.listener.Addr().(*net.TCPAddr)
+
+	host := addr.IP.String()
+	if addr.IP.IsUnspecified() {
+		host = "localhost"
+	}
+
+	return "http://" + net.JoinHostPort(host, fmt.Sprint(addr.Port)) + "/"
+}
+
+// GetManagerCallbacks returns the callbacks that send the changes of the managers and services to the browsers. Must be provided to the
+// manager module (see `modules.AllModulesLoaderOptions`)
+func (s *Server) GetManagerCallbacks() *modmanager.Callbacks {
+	return &modmanager.Callbacks{
+		OnNewManager: func(man *manager.Manager) {
+			s.publishServices()
+		},
+		OnDeleteManager: func(man *manager.Manager) {
+			s.publishServices()
+		},
+		OnAddService: func(man *manager.Manager, svc *enhanced.EnhancedService) {
+			s.publishServices()
+		},
+		OnServiceStatusChanged: func(man *manager.Manager, svc *enhanced.EnhancedService) {
+			s.publish(eventStatus, control.NewServiceInfo(man, svc))
+		},
+	}
+}
+
+// Close stops the server and ends the event streams. Can be called multiple times
+func (s *Server) Close() error {
+	// Ends the event streams
+	if s.opts.Runtime.Logger.RemoveSink(s.broker) != nil {
+		s.broker.Close()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+
+	err := s.httpServer.Shutdown(ctx)
+	if err != nil {
+		s.httpServer.Close() // Pending requests after the timeout
+	}
+	<-s.done
+
+	if err != nil {
+		return fmt.Errorf("error shutting down web server: %w", err)
+	}
+
+	return nil
+}
+
+// publish sends an event to the browsers
+func (s *Server) publish(name string, data any) {
+	ev, err := newEvent(name, data)
+	if err != nil {
+		s.opts.Runtime.Logger.LogError(fmt.Errorf("error encoding '%s' event of web dashboard: %w", name, err))
+		return
+	}
+
+	s.broker.publish(ev)
+}
+
+// publishServices sends all managers and their services to the browsers
+func (s *Server) publishServices() {
+	s.publish(eventServices, control.ListManagers(s.opts.Runtime))
+}
+
+func (s *Server) handleServices(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, control.ListManagers(s.opts.Runtime))
+}
+
+func (s *Server) handleAction(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get(actionHeader) == "" {
+		writeError(w, http.StatusForbidden, fmt.Errorf("missing '%s' header", actionHeader))
+		return
+	}
+
+	action := r.PathValue("action")
+	if !actions[action] {
+		writeError(w, http.StatusNotFound, fmt.Errorf("invalid action '%s'", action))
+		return
+	}
+
+	err := control.RunAction(s.opts.Runtime, action, &control.TargetParams{
+		Target: r.PathValue("manager") + "/" + r.PathValue("service"),
+		Force:  r.URL.Query().Get("force") == "true",
+	})
+	if err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleEvents streams the events to a browser. Starts with the services and the buffered logs
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming is not supported"))
+		return
+	}
+
+	logs, subscriber := s.broker.subscribe()
+	defer s.broker.unsubscribe(subscriber)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	services, err := newEvent(eventServices, control.ListManagers(s.opts.Runtime))
+	if err != nil {
+		return
+	}
+	writeEvent(w, services)
+
+	for _, ev := range logs {
+		writeEvent(w, ev)
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case ev, ok := <-subscriber:
+			if !ok {
+				return // Server closed
+			}
+
+			writeEvent(w, ev)
+			flusher.Flush()
+
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, ev event) {
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.name, ev.data)
+}
+
+func writeJSON(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
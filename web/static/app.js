@@ -0,0 +1,205 @@
+'use strict';
+
+// Maximum number of log lines kept in the page
+const maxLines = 5000;
+
+const managersList = document.getElementById('managers');
+const allServicesButton = document.getElementById('all-services');
+const logsView = document.getElementById('logs');
+const filterInput = document.getElementById('filter');
+const followCheckbox = document.getElementById('follow');
+const connectionLabel = document.getElementById('connection');
+
+let managers = [];
+let logs = []; // Received log entries
+let selected = null; // Service shown in the logs view ('<manager>/<service>'). All services if null
+
+// Services
+
+function renderServices() {
+	managersList.replaceChildren();
+
+	for (const manager of managers) {
+		const item = document.createElement('li');
+		item.className = 'manager';
+
+		const name = document.createElement('span');
+		name.textContent = manager.name;
+		item.appendChild(name);
+
+		const services = document.createElement('ul');
+		for (const service of manager.services) {
+			services.appendChild(renderService(service));
+		}
+		item.appendChild(services);
+
+		managersList.appendChild(item);
+	}
+
+	allServicesButton.classList.toggle('selected', selected === null);
+}
+
+function renderService(service) {
+	const id = service.manager + '/' + service.name;
+
+	const item = document.createElement('li');
+	item.className = 'service';
+	item.dataset.id = id;
+	item.classList.toggle('selected', selected === id);
+	item.addEventListener('click', () => selectService(id));
+
+	const name = document.createElement('span');
+	name.className = 'name';
+	name.textContent = service.name;
+	item.appendChild(name);
+
+	const status = document.createElement('span');
+	status.className = 'status status-' + service.status;
+	status.textContent = service.status;
+	item.appendChild(status);
+
+	for (const action of ['start', 'restart', 'stop']) {
+		const button = document.createElement('button');
+		button.textContent = action;
+		button.title = action + ' ' + id + ' (shift-click to force)';
+		button.addEventListener('click', (event) => {
+			event.stopPropagation();
+			runAction(service, action, event.shiftKey);
+		});
+		item.appendChild(button);
+	}
+
+	return item;
+}
+
+function updateService(info) {
+	for (const manager of managers) {
+		if (manager.name !== info.manager) {
+			continue;
+		}
+
+		const index = manager.services.findIndex((service) => service.name === info.name);
+		if (index !== -1) {
+			manager.services[index] = info;
+			renderServices();
+			return;
+		}
+	}
+
+	// The service list is outdated (e.g. the script was re-run)
+	fetch('api/services')
+		.then((response) => response.json())
+		.then((list) => {
+			managers = list;
+			renderServices();
+		});
+}
+
+function selectService(id) {
+	selected = id;
+	renderServices();
+	renderLogs();
+}
+
+async function runAction(service, action, force) {
+	const url = 'api/services/' + encodeURIComponent(service.manager) + '/' + encodeURIComponent(service.name) + '/' + action +
+		(force ? '?force=true' : '');
+
+	const response = await fetch(url, {
+		method: 'POST',
+		headers: { 'X-Falcula-Action': action },
+	});
+
+	if (!response.ok) {
+		const body = await response.json();
+		alert('Error running ' + action + ' on ' + service.name + ': ' + body.error);
+	}
+}
+
+// Logs
+
+function matchesLog(entry) {
+	if (selected !== null && selected.split('/')[1] !== entry.service) {
+		return false;
+	}
+
+	const filter = filterInput.value.toLowerCase();
+	return filter === '' || entry.message.toLowerCase().includes(filter);
+}
+
+function renderLog(entry) {
+	const line = document.createElement('div');
+	line.className = 'level-' + (entry.level || entry.stream);
+
+	const time = new Date(entry.time).toLocaleTimeString();
+	const service = document.createElement('span');
+	service.className = 'service-name';
+	service.textContent = time + ' ' + entry.service + ' ';
+	line.appendChild(service);
+	line.appendChild(document.createTextNode(entry.message));
+
+	return line;
+}
+
+function renderLogs() {
+	logsView.replaceChildren(...logs.filter(matchesLog).map(renderLog));
+	scrollLogs();
+}
+
+function addLog(entry) {
+	logs.push(entry);
+	if (logs.length > maxLines) {
+		logs.shift();
+		if (logsView.firstChild && matchesLog(logs[0])) {
+			logsView.firstChild.remove();
+		}
+	}
+
+	if (matchesLog(entry)) {
+		logsView.appendChild(renderLog(entry));
+		scrollLogs();
+	}
+}
+
+function scrollLogs() {
+	if (followCheckbox.checked) {
+		logsView.scrollTop = logsView.scrollHeight;
+	}
+}
+
+// Events
+
+function connect() {
+	const events = new EventSource('api/events');
+
+	events.addEventListener('open', () => {
+		connectionLabel.textContent = 'Connected';
+		connectionLabel.className = 'connected';
+
+		// The server sends the buffered logs again
+		logs = [];
+		logsView.replaceChildren();
+	});
+
+	events.addEventListener('error', () => {
+		connectionLabel.textContent = 'Disconnected';
+		connectionLabel.className = 'disconnected';
+	});
+
+	events.addEventListener('services', (event) => {
+		managers = JSON.parse(event.data);
+		renderServices();
+	});
+
+	events.addEventListener('status', (event) => updateService(JSON.parse(event.data)));
+	events.addEventListener('log', (event) => addLog(JSON.parse(event.data)));
+}
+
+allServicesButton.addEventListener('click', () => selectService(null));
+filterInput.addEventListener('input', renderLogs);
+document.getElementById('clear').addEventListener('click', () => {
+	logs = [];
+	renderLogs();
+});
+
+connect();